@@ -0,0 +1,209 @@
+package opendataug
+
+import (
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// AddressStyle selects one of the predefined address layouts
+type AddressStyle int
+
+const (
+	// AddressFull lists every level from the village up to the district on
+	// one line
+	AddressFull AddressStyle = iota
+	// AddressShort lists the village, subcounty and district on one line
+	AddressShort
+	// AddressDistrictFirst lists every level from the district down to the
+	// village on one line
+	AddressDistrictFirst
+	// AddressPostal lists every level from the village up to the district
+	// on its own line, with the district in capitals
+	AddressPostal
+)
+
+// Case controls the capitalisation of unit names in an address
+type Case int
+
+const (
+	// CaseTitle capitalises the first letter of each word and lower-cases
+	// the rest, leaving abbreviations and roman numerals alone
+	CaseTitle Case = iota
+	// CaseUpper upper-cases every name
+	CaseUpper
+	// CaseAsIs leaves names as returned by the API
+	CaseAsIs
+)
+
+// AddressFields are the names available to address templates. Names are
+// already capitalised and missing levels are empty, or set to the
+// formatter's Missing text.
+type AddressFields struct {
+	Region    string
+	District  string
+	County    string
+	Subcounty string
+	Parish    string
+	Village   string
+}
+
+// AddressFormatter formats an AdminPath as a human-readable address. The
+// zero value formats every level from the village up on one line separated
+// by commas.
+type AddressFormatter struct {
+	// Levels lists the levels to include in order. Nil means every level
+	// from the village up to the district.
+	Levels []Level
+	// Separator is placed between names. Empty means ", ".
+	Separator string
+	// Case controls the capitalisation of names
+	Case Case
+	// UpperLevels lists levels whose names are always upper-cased
+	UpperLevels []Level
+	// Missing is used in place of levels the path does not reach. When it
+	// is empty those levels are left out.
+	Missing string
+
+	template *template.Template
+}
+
+// NewAddressFormatter returns a formatter for one of the predefined styles
+func NewAddressFormatter(style AddressStyle) *AddressFormatter {
+	switch style {
+	case AddressShort:
+		return &AddressFormatter{
+			Levels: []Level{LevelVillage, LevelSubcounty, LevelDistrict},
+		}
+	case AddressDistrictFirst:
+		return &AddressFormatter{
+			Levels: []Level{LevelDistrict, LevelCounty, LevelSubcounty, LevelParish, LevelVillage},
+		}
+	case AddressPostal:
+		return &AddressFormatter{
+			Separator:   "\n",
+			UpperLevels: []Level{LevelDistrict},
+		}
+	}
+	return &AddressFormatter{}
+}
+
+// NewTemplateAddressFormatter returns a formatter that renders a text/template
+// with AddressFields, for example "{{.Village}} village, {{.District}}"
+func NewTemplateAddressFormatter(text string) (*AddressFormatter, error) {
+	tmpl, err := template.New("address").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressFormatter{template: tmpl}, nil
+}
+
+// Format formats path as an address. It only fails if a template cannot be
+// executed.
+func (f *AddressFormatter) Format(path *AdminPath) (string, error) {
+	if f.template != nil {
+		var b strings.Builder
+		if err := f.template.Execute(&b, f.Fields(path)); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+
+	levels := f.Levels
+	if levels == nil {
+		levels = []Level{LevelVillage, LevelParish, LevelSubcounty, LevelCounty, LevelDistrict}
+	}
+
+	separator := f.Separator
+	if separator == "" {
+		separator = ", "
+	}
+
+	var parts []string
+	for _, level := range levels {
+		if name := f.name(path, level); name != "" {
+			parts = append(parts, name)
+		}
+	}
+
+	return strings.Join(parts, separator), nil
+}
+
+// Fields returns the capitalised names of every level of path
+func (f *AddressFormatter) Fields(path *AdminPath) AddressFields {
+	region := f.capitalise(path.Region.Name)
+	if region == "" {
+		region = f.Missing
+	}
+
+	return AddressFields{
+		Region:    region,
+		District:  f.name(path, LevelDistrict),
+		County:    f.name(path, LevelCounty),
+		Subcounty: f.name(path, LevelSubcounty),
+		Parish:    f.name(path, LevelParish),
+		Village:   f.name(path, LevelVillage),
+	}
+}
+
+func (f *AddressFormatter) name(path *AdminPath, level Level) string {
+	name := strings.TrimSpace(path.Name(level))
+	if name == "" {
+		return f.Missing
+	}
+
+	for _, upper := range f.UpperLevels {
+		if upper == level {
+			return strings.ToUpper(name)
+		}
+	}
+
+	return f.capitalise(name)
+}
+
+func (f *AddressFormatter) capitalise(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+
+	switch f.Case {
+	case CaseUpper:
+		return strings.ToUpper(name)
+	case CaseAsIs:
+		return name
+	}
+
+	words := strings.Split(name, " ")
+	for i, word := range words {
+		words[i] = titleWord(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// titleWord capitalises each hyphenated part of word. Parts that look like
+// abbreviations ("T/C", "HQ") or roman numerals are kept as they are.
+func titleWord(word string) string {
+	parts := strings.Split(word, "-")
+	for i, part := range parts {
+		if isAbbreviation(part) {
+			continue
+		}
+		runes := []rune(strings.ToLower(part))
+		if len(runes) > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		parts[i] = string(runes)
+	}
+	return strings.Join(parts, "-")
+}
+
+func isAbbreviation(part string) bool {
+	if part == "" || strings.ToUpper(part) != part {
+		return false
+	}
+	if strings.ContainsAny(part, "/.") {
+		return true
+	}
+	if isRomanNumeral(part) {
+		return true
+	}
+	return len([]rune(part)) <= 2
+}
@@ -0,0 +1,117 @@
+package opendataug
+
+import "testing"
+
+func testPath() *AdminPath {
+	return &AdminPath{
+		Region:    Region{ID: "region-1", Name: "Central"},
+		District:  &District{ID: "district-1", Name: "KAMPALA"},
+		County:    &County{ID: "county-1", Name: "nakawa"},
+		Subcounty: &Subcounty{ID: "subcounty-1", Name: "Nakawa  Division"},
+		Parish:    &Parish{ID: "parish-1", Name: "kiwatule"},
+		Village:   &Village{ID: "village-1", Name: "kiwatule-kigoowa T/C"},
+	}
+}
+
+func TestAddressFormatterStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    AddressStyle
+		expected string
+	}{
+		{
+			name:     "Full",
+			style:    AddressFull,
+			expected: "Kiwatule-Kigoowa T/C, Kiwatule, Nakawa Division, Nakawa, Kampala",
+		},
+		{
+			name:     "Short",
+			style:    AddressShort,
+			expected: "Kiwatule-Kigoowa T/C, Nakawa Division, Kampala",
+		},
+		{
+			name:     "District first",
+			style:    AddressDistrictFirst,
+			expected: "Kampala, Nakawa, Nakawa Division, Kiwatule, Kiwatule-Kigoowa T/C",
+		},
+		{
+			name:     "Postal",
+			style:    AddressPostal,
+			expected: "Kiwatule-Kigoowa T/C\nKiwatule\nNakawa Division\nNakawa\nKAMPALA",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			address, err := NewAddressFormatter(tc.style).Format(testPath())
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if address != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, address)
+			}
+		})
+	}
+}
+
+func TestAddressFormatterMissingLevels(t *testing.T) {
+	path := testPath()
+	path.Parish = nil
+	path.Village = nil
+
+	f := NewAddressFormatter(AddressFull)
+
+	address, _ := f.Format(path)
+	if address != "Nakawa Division, Nakawa, Kampala" {
+		t.Errorf("Expected missing levels to be left out, got %q", address)
+	}
+
+	f.Missing = "-"
+	address, _ = f.Format(path)
+	if address != "-, -, Nakawa Division, Nakawa, Kampala" {
+		t.Errorf("Expected missing levels to be replaced, got %q", address)
+	}
+}
+
+func TestAddressFormatterOptions(t *testing.T) {
+	f := &AddressFormatter{
+		Levels:    []Level{LevelParish, LevelDistrict},
+		Separator: " / ",
+		Case:      CaseUpper,
+	}
+
+	address, _ := f.Format(testPath())
+	if address != "KIWATULE / KAMPALA" {
+		t.Errorf("Expected KIWATULE / KAMPALA, got %q", address)
+	}
+
+	f = &AddressFormatter{Levels: []Level{LevelSubcounty}, Case: CaseAsIs}
+	address, _ = f.Format(testPath())
+	if address != "Nakawa Division" {
+		t.Errorf("Expected Nakawa Division, got %q", address)
+	}
+}
+
+func TestTemplateAddressFormatter(t *testing.T) {
+	f, err := NewTemplateAddressFormatter("{{.Village}} village, {{.District}} ({{.Region}})")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	address, err := f.Format(testPath())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if address != "Kiwatule-Kigoowa T/C village, Kampala (Central)" {
+		t.Errorf("Expected Kiwatule-Kigoowa T/C village, Kampala (Central), got %q", address)
+	}
+
+	if _, err := NewTemplateAddressFormatter("{{.Village"); err == nil {
+		t.Error("Expected an error for an invalid template, got nil")
+	}
+
+	f, _ = NewTemplateAddressFormatter("{{.Street}}")
+	if _, err := f.Format(testPath()); err == nil {
+		t.Error("Expected an error for an unknown field, got nil")
+	}
+}
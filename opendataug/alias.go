@@ -0,0 +1,189 @@
+package opendataug
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Alias maps a name that is not a unit's current name, such as a historical
+// name, an alternative spelling or an abbreviation, to the unit's ID
+type Alias struct {
+	Name  string `json:"name"`
+	Level Level  `json:"level"`
+	ID    string `json:"id"`
+	// Kind describes the alias, for example "historical", "spelling" or
+	// "abbreviation". It is informational only.
+	Kind string `json:"kind,omitempty"`
+}
+
+type levelName struct {
+	level Level
+	key   string
+}
+
+// AliasRegistry resolves aliases to unit IDs. Names are compared by their
+// DefaultNormalizer keys. An AliasRegistry is safe for concurrent use.
+type AliasRegistry struct {
+	mu      sync.RWMutex
+	aliases map[levelName][]Alias
+}
+
+// NewAliasRegistry returns a registry holding aliases
+func NewAliasRegistry(aliases ...Alias) *AliasRegistry {
+	r := &AliasRegistry{aliases: make(map[levelName][]Alias)}
+	for _, alias := range aliases {
+		r.Add(alias)
+	}
+	return r
+}
+
+// Add registers alias. Registering the same name, level and ID twice has no
+// effect.
+func (r *AliasRegistry) Add(alias Alias) {
+	key := levelName{level: alias.Level, key: DefaultNormalizer.Key(alias.Name)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.aliases[key] {
+		if existing.ID == alias.ID {
+			return
+		}
+	}
+	r.aliases[key] = append(r.aliases[key], alias)
+}
+
+// Resolve returns the aliases registered for name at level
+func (r *AliasRegistry) Resolve(level Level, name string) []Alias {
+	key := levelName{level: level, key: DefaultNormalizer.Key(name)}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Alias(nil), r.aliases[key]...)
+}
+
+// Len returns the number of registered aliases
+func (r *AliasRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n := 0
+	for _, aliases := range r.aliases {
+		n += len(aliases)
+	}
+	return n
+}
+
+// LoadAliases reads aliases from a .json or .csv file, chosen by extension
+func LoadAliases(path string) (*AliasRegistry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReadAliasesJSON(f)
+	case ".csv":
+		return ReadAliasesCSV(f)
+	}
+	return nil, fmt.Errorf("opendataug: unsupported alias file %s", path)
+}
+
+// ReadAliasesJSON reads a JSON array of aliases
+func ReadAliasesJSON(r io.Reader) (*AliasRegistry, error) {
+	var aliases []Alias
+	if err := json.NewDecoder(r).Decode(&aliases); err != nil {
+		return nil, fmt.Errorf("reading aliases: %w", err)
+	}
+
+	for i, alias := range aliases {
+		if err := alias.validate(); err != nil {
+			return nil, fmt.Errorf("reading aliases: entry %d: %w", i, err)
+		}
+	}
+
+	return NewAliasRegistry(aliases...), nil
+}
+
+// ReadAliasesCSV reads aliases from CSV with a header row naming the name,
+// level and id columns and optionally a kind column, in any order
+func ReadAliasesCSV(r io.Reader) (*AliasRegistry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading aliases: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "level", "id"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("reading aliases: missing %s column", required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	registry := NewAliasRegistry()
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading aliases: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		level, err := ParseLevel(field(record, "level"))
+		if err != nil {
+			return nil, fmt.Errorf("reading aliases: line %d: %w", line, err)
+		}
+
+		alias := Alias{
+			Name:  field(record, "name"),
+			Level: level,
+			ID:    field(record, "id"),
+			Kind:  field(record, "kind"),
+		}
+		if err := alias.validate(); err != nil {
+			return nil, fmt.Errorf("reading aliases: line %d: %w", line, err)
+		}
+		registry.Add(alias)
+	}
+
+	return registry, nil
+}
+
+func (a Alias) validate() error {
+	switch {
+	case strings.TrimSpace(a.Name) == "":
+		return errors.New("alias has no name")
+	case !a.Level.Valid():
+		return errors.New("alias has no level")
+	case a.ID == "":
+		return errors.New("alias has no id")
+	}
+	return nil
+}
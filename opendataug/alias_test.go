@@ -0,0 +1,106 @@
+package opendataug
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAliasesJSON(t *testing.T) {
+	registry, err := ReadAliasesJSON(strings.NewReader(`[
+		{"name": "Sembabule", "level": "district", "id": "district-5", "kind": "spelling"},
+		{"name": "Kampala Central", "level": "county", "id": "county-1", "kind": "historical"}
+	]`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	aliases := registry.Resolve(LevelDistrict, "SEMBABULE DISTRICT")
+	expected := []Alias{{Name: "Sembabule", Level: LevelDistrict, ID: "district-5", Kind: "spelling"}}
+	if !reflect.DeepEqual(aliases, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, aliases)
+	}
+
+	if aliases := registry.Resolve(LevelCounty, "Sembabule"); len(aliases) != 0 {
+		t.Errorf("Expected aliases to be scoped to their level, got %+v", aliases)
+	}
+}
+
+func TestReadAliasesCSV(t *testing.T) {
+	registry, err := ReadAliasesCSV(strings.NewReader(
+		"level,name,id,kind\n" +
+			"district,Sembabule,district-5,spelling\n" +
+			"sub-county,Nakawa S/C,subcounty-1,abbreviation\n" +
+			"district,Sembabule,district-5,spelling\n",
+	))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if registry.Len() != 2 {
+		t.Errorf("Expected 2 aliases, got %d", registry.Len())
+	}
+
+	aliases := registry.Resolve(LevelSubcounty, "nakawa")
+	if len(aliases) != 1 || aliases[0].ID != "subcounty-1" {
+		t.Errorf("Expected subcounty-1, got %+v", aliases)
+	}
+}
+
+func TestReadAliasesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		csv   bool
+	}{
+		{name: "Invalid JSON", input: `[{"name": }]`},
+		{name: "Missing ID", input: `[{"name": "Sembabule", "level": "district"}]`},
+		{name: "Unknown level", input: `[{"name": "Sembabule", "level": "region", "id": "region-1"}]`},
+		{name: "Missing CSV column", input: "name,id\nSembabule,district-5\n", csv: true},
+		{name: "Unknown CSV level", input: "name,level,id\nSembabule,region,district-5\n", csv: true},
+		{name: "Empty CSV name", input: "name,level,id\n,district,district-5\n", csv: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			if tc.csv {
+				_, err = ReadAliasesCSV(strings.NewReader(tc.input))
+			} else {
+				_, err = ReadAliasesJSON(strings.NewReader(tc.input))
+			}
+			if err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadAliases(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "aliases.json")
+	if err := os.WriteFile(jsonPath, []byte(`[{"name": "Sembabule", "level": "district", "id": "district-5"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	csvPath := filepath.Join(dir, "aliases.csv")
+	if err := os.WriteFile(csvPath, []byte("name,level,id\nSembabule,district,district-5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{jsonPath, csvPath} {
+		registry, err := LoadAliases(path)
+		if err != nil {
+			t.Fatalf("Expected no error loading %s, got %v", path, err)
+		}
+		if registry.Len() != 1 {
+			t.Errorf("Expected 1 alias in %s, got %d", path, registry.Len())
+		}
+	}
+
+	if _, err := LoadAliases(filepath.Join(dir, "aliases.txt")); err == nil {
+		t.Error("Expected an error for an unsupported extension, got nil")
+	}
+}
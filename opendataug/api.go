@@ -0,0 +1,57 @@
+package opendataug
+
+import (
+	"context"
+	"time"
+)
+
+// API is the set of read methods shared by the live Client and the
+// OfflineClient, so callers can swap one backend for the other. Every method
+// takes a context. The single-level getters without one, such as
+// Client.GetDistricts, predate context support and are kept on both clients
+// for compatibility, but are not part of API.
+type API interface {
+	GetDistrictsContext(ctx context.Context) ([]District, error)
+	GetDistrictContext(ctx context.Context, id DistrictID) (*District, error)
+
+	GetCountiesContext(ctx context.Context) ([]County, error)
+	GetCountyContext(ctx context.Context, id CountyID) (*County, error)
+	GetCountiesByDistrictContext(ctx context.Context, districtID DistrictID) ([]County, error)
+
+	GetSubcountiesContext(ctx context.Context) ([]Subcounty, error)
+	GetSubcountyContext(ctx context.Context, id SubcountyID) (*Subcounty, error)
+	GetSubcountiesByCountyContext(ctx context.Context, countyID CountyID) ([]Subcounty, error)
+	GetSubcountiesByDistrict(ctx context.Context, districtID DistrictID) ([]Subcounty, error)
+
+	GetParishesContext(ctx context.Context) ([]Parish, error)
+	GetParishContext(ctx context.Context, id ParishID) (*Parish, error)
+	GetParishesBySubcountyContext(ctx context.Context, subcountyID SubcountyID) ([]Parish, error)
+	GetParishesByCounty(ctx context.Context, countyID CountyID) ([]Parish, error)
+	GetParishesByDistrict(ctx context.Context, districtID DistrictID) ([]Parish, error)
+
+	GetVillagesContext(ctx context.Context) ([]Village, error)
+	GetVillageContext(ctx context.Context, id VillageID) (*Village, error)
+	GetVillagesByParishContext(ctx context.Context, parishID ParishID) ([]Village, error)
+	GetVillagesBySubcounty(ctx context.Context, subcountyID SubcountyID) ([]Village, error)
+	GetVillagesByCounty(ctx context.Context, countyID CountyID) ([]Village, error)
+	GetVillagesByDistrict(ctx context.Context, districtID DistrictID) ([]Village, error)
+
+	GetCountyByCode(ctx context.Context, code string) (*County, error)
+	GetCountyByCodeInDistrict(ctx context.Context, districtID DistrictID, code string) (*County, error)
+	GetSubcountyByCode(ctx context.Context, code string) (*Subcounty, error)
+	GetSubcountyByCodeInCounty(ctx context.Context, countyID CountyID, code string) (*Subcounty, error)
+	GetParishByCode(ctx context.Context, code string) (*Parish, error)
+	GetParishByCodeInSubcounty(ctx context.Context, subcountyID SubcountyID, code string) (*Parish, error)
+	GetVillageByCode(ctx context.Context, code string) (*Village, error)
+	GetVillageByCodeInParish(ctx context.Context, parishID ParishID, code string) (*Village, error)
+
+	GetCountiesUpdatedSince(ctx context.Context, since time.Time) ([]County, time.Time, error)
+	GetSubcountiesUpdatedSince(ctx context.Context, since time.Time) ([]Subcounty, time.Time, error)
+	GetParishesUpdatedSince(ctx context.Context, since time.Time) ([]Parish, time.Time, error)
+	GetVillagesUpdatedSince(ctx context.Context, since time.Time) ([]Village, time.Time, error)
+}
+
+var (
+	_ API = (*Client)(nil)
+	_ API = (*OfflineClient)(nil)
+)
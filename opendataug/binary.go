@@ -0,0 +1,354 @@
+package opendataug
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The binary format stores a Dataset compactly and loads much faster than
+// JSON. It starts with a header of the magic bytes "ODUG" and a version byte,
+// followed by a table of every distinct string, and then the districts,
+// counties, subcounties, parishes and villages, each level prefixed with its
+// count. Records refer to strings by their uvarint index in the table, and
+// timestamps are stored as the index of their text plus one, with zero for a
+// missing timestamp. Parents are the uvarint index plus one of the parent in
+// the level above, or zero followed by the parent ID for parents that are
+// missing from the dataset.
+
+// BinaryVersion is the version of the binary format written by WriteBinary
+const BinaryVersion = 1
+
+var binaryMagic = [4]byte{'O', 'D', 'U', 'G'}
+
+// ErrBinaryFormat is returned when data is not a valid binary dataset
+var ErrBinaryFormat = errors.New("opendataug: invalid binary dataset")
+
+// WriteBinary writes ds to w in the binary format
+func WriteBinary(w io.Writer, ds *Dataset) error {
+	data, err := ds.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// ReadBinary reads a dataset in the binary format from r
+func ReadBinary(r io.Reader) (*Dataset, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &Dataset{}
+	if err := ds.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (ds *Dataset) MarshalBinary() ([]byte, error) {
+	e := newBinaryEncoder(ds)
+
+	// The first pass only fills the string table, which has to be written
+	// before the records that refer to it
+	e.appendRecords(nil, ds)
+	e.collecting = false
+
+	buf := append([]byte(nil), binaryMagic[:]...)
+	buf = append(buf, BinaryVersion)
+
+	buf = binary.AppendUvarint(buf, uint64(len(e.strings)))
+	for _, s := range e.strings {
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		buf = append(buf, s...)
+	}
+
+	return e.appendRecords(buf, ds), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (ds *Dataset) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+1 || [4]byte(data[:4]) != binaryMagic {
+		return fmt.Errorf("%w: missing header", ErrBinaryFormat)
+	}
+	if data[4] != BinaryVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrBinaryFormat, data[4])
+	}
+
+	d := &binaryDecoder{data: data, pos: len(binaryMagic) + 1}
+
+	n := d.count()
+	d.strings = make([]string, n)
+	for i := range d.strings {
+		size := d.count()
+		if d.err != nil {
+			break
+		}
+		d.strings[i] = string(d.data[d.pos : d.pos+size])
+		d.pos += size
+	}
+	d.stamps = make([]*Timestamp, len(d.strings))
+
+	var out Dataset
+
+	out.Districts = make([]District, d.count())
+	for i := range out.Districts {
+		out.Districts[i] = District{
+			ID:         DistrictID(d.string()),
+			Name:       d.string(),
+			TownStatus: d.byte() == 1,
+			RegionID:   d.string(),
+			RegionName: d.string(),
+		}
+	}
+
+	out.Counties = make([]County, d.count())
+	for i := range out.Counties {
+		c := &out.Counties[i]
+		c.ID, c.Name, c.Code = CountyID(d.string()), d.string(), d.string()
+		c.DistrictID = DistrictID(d.parent(func(i int) string { return string(out.Districts[i].ID) }, len(out.Districts)))
+		c.CreatedAt, c.UpdatedAt = d.timestamp(), d.timestamp()
+	}
+
+	out.Subcounties = make([]Subcounty, d.count())
+	for i := range out.Subcounties {
+		s := &out.Subcounties[i]
+		s.ID, s.Name, s.Code = SubcountyID(d.string()), d.string(), d.string()
+		s.CountyID = CountyID(d.parent(func(i int) string { return string(out.Counties[i].ID) }, len(out.Counties)))
+		s.CreatedAt, s.UpdatedAt = d.timestamp(), d.timestamp()
+	}
+
+	out.Parishes = make([]Parish, d.count())
+	for i := range out.Parishes {
+		p := &out.Parishes[i]
+		p.ID, p.Name, p.Code = ParishID(d.string()), d.string(), d.string()
+		p.SubcountyID = SubcountyID(d.parent(func(i int) string { return string(out.Subcounties[i].ID) }, len(out.Subcounties)))
+		p.CreatedAt, p.UpdatedAt = d.timestamp(), d.timestamp()
+	}
+
+	out.Villages = make([]Village, d.count())
+	for i := range out.Villages {
+		v := &out.Villages[i]
+		v.ID, v.Name, v.Code = VillageID(d.string()), d.string(), d.string()
+		v.ParishID = ParishID(d.parent(func(i int) string { return string(out.Parishes[i].ID) }, len(out.Parishes)))
+		v.CreatedAt, v.UpdatedAt = d.timestamp(), d.timestamp()
+	}
+
+	if d.err == nil && d.pos != len(d.data) {
+		d.err = fmt.Errorf("%w: %d trailing bytes", ErrBinaryFormat, len(d.data)-d.pos)
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	*ds = out
+	return nil
+}
+
+// binaryEncoder holds the string table and parent positions of a dataset
+// being encoded
+type binaryEncoder struct {
+	collecting bool
+	strings    []string
+	index      map[string]int
+	// positions maps the IDs of each level to their first position
+	positions map[Level]map[string]int
+}
+
+func newBinaryEncoder(ds *Dataset) *binaryEncoder {
+	e := &binaryEncoder{
+		collecting: true,
+		index:      make(map[string]int),
+		positions:  make(map[Level]map[string]int),
+	}
+
+	for level, us := range ds.units() {
+		positions := make(map[string]int, len(us))
+		for i, u := range us {
+			if _, ok := positions[u.UnitID()]; !ok {
+				positions[u.UnitID()] = i
+			}
+		}
+		e.positions[level] = positions
+	}
+
+	return e
+}
+
+func (e *binaryEncoder) appendRecords(buf []byte, ds *Dataset) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(ds.Districts)))
+	for _, d := range ds.Districts {
+		buf = e.appendString(buf, string(d.ID))
+		buf = e.appendString(buf, d.Name)
+		if d.TownStatus {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+		buf = e.appendString(buf, d.RegionID)
+		buf = e.appendString(buf, d.RegionName)
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(ds.Counties)))
+	for _, c := range ds.Counties {
+		buf = e.appendUnit(buf, string(c.ID), c.Name, c.Code, LevelDistrict, string(c.DistrictID), c.CreatedAt, c.UpdatedAt)
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(ds.Subcounties)))
+	for _, s := range ds.Subcounties {
+		buf = e.appendUnit(buf, string(s.ID), s.Name, s.Code, LevelCounty, string(s.CountyID), s.CreatedAt, s.UpdatedAt)
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(ds.Parishes)))
+	for _, p := range ds.Parishes {
+		buf = e.appendUnit(buf, string(p.ID), p.Name, p.Code, LevelSubcounty, string(p.SubcountyID), p.CreatedAt, p.UpdatedAt)
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(ds.Villages)))
+	for _, v := range ds.Villages {
+		buf = e.appendUnit(buf, string(v.ID), v.Name, v.Code, LevelParish, string(v.ParishID), v.CreatedAt, v.UpdatedAt)
+	}
+
+	return buf
+}
+
+func (e *binaryEncoder) appendUnit(buf []byte, id, name, code string, parentLevel Level, parentID string, created, updated Timestamp) []byte {
+	buf = e.appendString(buf, id)
+	buf = e.appendString(buf, name)
+	buf = e.appendString(buf, code)
+
+	if i, ok := e.positions[parentLevel][parentID]; ok {
+		buf = binary.AppendUvarint(buf, uint64(i)+1)
+	} else {
+		buf = binary.AppendUvarint(buf, 0)
+		buf = e.appendString(buf, parentID)
+	}
+
+	buf = e.appendTimestamp(buf, created)
+	return e.appendTimestamp(buf, updated)
+}
+
+// lookup returns the index of s in the string table, adding it while the
+// table is being collected
+func (e *binaryEncoder) lookup(s string) int {
+	i, ok := e.index[s]
+	if !ok && e.collecting {
+		i = len(e.strings)
+		e.index[s] = i
+		e.strings = append(e.strings, s)
+	}
+	return i
+}
+
+func (e *binaryEncoder) appendString(buf []byte, s string) []byte {
+	return binary.AppendUvarint(buf, uint64(e.lookup(s)))
+}
+
+func (e *binaryEncoder) appendTimestamp(buf []byte, t Timestamp) []byte {
+	if t.IsZero() {
+		return binary.AppendUvarint(buf, 0)
+	}
+	return binary.AppendUvarint(buf, uint64(e.lookup(t.String()))+1)
+}
+
+// binaryDecoder reads values from data, recording the first error and
+// returning zero values after it
+type binaryDecoder struct {
+	data    []byte
+	pos     int
+	strings []string
+	// stamps caches each timestamp string once it has been parsed
+	stamps []*Timestamp
+	err    error
+}
+
+func (d *binaryDecoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: "+format, append([]any{ErrBinaryFormat}, args...)...)
+	}
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		d.fail("truncated at byte %d", d.pos)
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+// count reads a length, which can never exceed the bytes left since every
+// item takes at least one byte
+func (d *binaryDecoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)-d.pos) {
+		d.fail("count %d exceeds remaining data at byte %d", n, d.pos)
+		return 0
+	}
+	return int(n)
+}
+
+func (d *binaryDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if d.pos >= len(d.data) {
+		d.fail("truncated at byte %d", d.pos)
+		return 0
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b
+}
+
+func (d *binaryDecoder) string() string {
+	i := d.uvarint()
+	if i >= uint64(len(d.strings)) {
+		d.fail("string index %d out of range", i)
+		return ""
+	}
+	return d.strings[i]
+}
+
+func (d *binaryDecoder) timestamp() Timestamp {
+	i := d.uvarint()
+	if i == 0 || d.err != nil {
+		return Timestamp{}
+	}
+	i--
+	if i >= uint64(len(d.strings)) {
+		d.fail("timestamp index %d out of range", i)
+		return Timestamp{}
+	}
+
+	if d.stamps[i] == nil {
+		var t Timestamp
+		// Text that does not parse was stored from a Timestamp that kept its
+		// raw text, which UnmarshalText restores
+		_ = t.UnmarshalText([]byte(d.strings[i]))
+		d.stamps[i] = &t
+	}
+	return *d.stamps[i]
+}
+
+// parent reads a parent reference, using id to look up parents at a position
+// of the level above
+func (d *binaryDecoder) parent(id func(int) string, n int) string {
+	ref := d.uvarint()
+	if ref == 0 {
+		return d.string()
+	}
+	if ref > uint64(n) {
+		d.fail("parent index %d out of range", ref-1)
+		return ""
+	}
+	return id(int(ref - 1))
+}
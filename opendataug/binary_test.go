@@ -0,0 +1,203 @@
+package opendataug
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// jsonRoundTrip returns ds as it reads back from JSON, which is what the
+// binary format must reproduce
+func jsonRoundTrip(t testing.TB, ds *Dataset) *Dataset {
+	data, err := json.Marshal(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Dataset
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return &decoded
+}
+
+func binaryTestDataset(t testing.TB) *Dataset {
+	ds := testDataset()
+	created, err := ParseTimestamp("2023-05-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range ds.Villages {
+		ds.Villages[i].CreatedAt = created
+	}
+	ds.Villages[0].UpdatedAt = NewTimestamp(time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC))
+	ds.Counties[1].UpdatedAt, _ = ParseTimestamp("2024-02-03 04:05:06")
+	_ = ds.Counties[2].UpdatedAt.UnmarshalText([]byte("02/03/2024"))
+	// A duplicate ID is kept as it is, and children refer to the first unit
+	ds.Parishes = append(ds.Parishes, Parish{ID: "parish-1", Name: "Kiwatule (old)", SubcountyID: "subcounty-1"})
+	return ds
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	ds := binaryTestDataset(t)
+
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, ds); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	size := buf.Len()
+
+	got, err := ReadBinary(&buf)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := jsonRoundTrip(t, ds)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+	if got.Counties[2].UpdatedAt.Raw() != "02/03/2024" {
+		t.Errorf("Expected the unparsed timestamp to be kept, got %+v", got.Counties[2].UpdatedAt)
+	}
+	if got.Villages[5].ParishID != "parish-missing" {
+		t.Errorf("Expected the dangling parent to be kept, got %q", got.Villages[5].ParishID)
+	}
+
+	data, _ := json.Marshal(ds)
+	if size >= len(data) {
+		t.Errorf("Expected the binary encoding to be smaller than %d bytes of JSON, got %d", len(data), size)
+	}
+}
+
+func TestBinaryEmpty(t *testing.T) {
+	data, err := (&Dataset{}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var ds Dataset
+	if err := ds.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ds.Districts)+len(ds.Villages) != 0 {
+		t.Errorf("Expected an empty dataset, got %+v", ds)
+	}
+}
+
+func TestBinaryInvalid(t *testing.T) {
+	valid, err := binaryTestDataset(t).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Empty", data: nil},
+		{name: "Wrong magic", data: []byte("JSON{}")},
+		{name: "Wrong version", data: append([]byte("ODUG"), BinaryVersion+1)},
+		{name: "Trailing bytes", data: append(append([]byte(nil), valid...), 0)},
+		{name: "Bad string index", data: append([]byte("ODUG"), BinaryVersion, 0, 1, 5)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var ds Dataset
+			if err := ds.UnmarshalBinary(tc.data); !errors.Is(err, ErrBinaryFormat) {
+				t.Errorf("Expected ErrBinaryFormat, got %v", err)
+			}
+		})
+	}
+
+	// Every truncation must fail cleanly rather than panic
+	for n := range len(valid) {
+		var ds Dataset
+		if err := ds.UnmarshalBinary(valid[:n]); err == nil {
+			t.Fatalf("Expected an error for %d of %d bytes, got nil", n, len(valid))
+		}
+	}
+}
+
+// benchmarkDataset builds a hierarchy about the size of Uganda's, with
+// roughly 58,000 villages
+func benchmarkDataset() *Dataset {
+	ds := &Dataset{}
+	created, _ := ParseTimestamp("2023-05-01T00:00:00Z")
+	updated, _ := ParseTimestamp("2024-01-15T08:30:00Z")
+
+	for d := range 146 {
+		districtID := DistrictID(fmt.Sprintf("district-%d", d))
+		ds.Districts = append(ds.Districts, District{ID: districtID, Name: fmt.Sprintf("District %d", d), RegionID: fmt.Sprintf("region-%d", d%4), RegionName: fmt.Sprintf("Region %d", d%4)})
+		for c := range 2 {
+			countyID := CountyID(fmt.Sprintf("%s-county-%d", districtID, c))
+			ds.Counties = append(ds.Counties, County{ID: countyID, Name: fmt.Sprintf("County %d", c), Code: fmt.Sprintf("C%d", c), DistrictID: districtID, CreatedAt: created, UpdatedAt: updated})
+			for s := range 5 {
+				subcountyID := SubcountyID(fmt.Sprintf("%s-subcounty-%d", countyID, s))
+				ds.Subcounties = append(ds.Subcounties, Subcounty{ID: subcountyID, Name: fmt.Sprintf("Subcounty %d", s), Code: fmt.Sprintf("S%d", s), CountyID: countyID, CreatedAt: created, UpdatedAt: updated})
+				for p := range 5 {
+					parishID := ParishID(fmt.Sprintf("%s-parish-%d", subcountyID, p))
+					ds.Parishes = append(ds.Parishes, Parish{ID: parishID, Name: fmt.Sprintf("Parish %d", p), Code: fmt.Sprintf("P%d", p), SubcountyID: subcountyID, CreatedAt: created, UpdatedAt: updated})
+					for v := range 8 {
+						ds.Villages = append(ds.Villages, Village{ID: VillageID(fmt.Sprintf("%s-village-%d", parishID, v)), Name: fmt.Sprintf("Village %d", v), Code: fmt.Sprintf("V%d", v), ParishID: parishID, CreatedAt: created, UpdatedAt: updated})
+					}
+				}
+			}
+		}
+	}
+
+	return ds
+}
+
+func BenchmarkDecodeJSON(b *testing.B) {
+	data, err := json.Marshal(benchmarkDataset())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+
+	for b.Loop() {
+		var ds Dataset
+		if err := json.Unmarshal(data, &ds); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeBinary(b *testing.B) {
+	data, err := benchmarkDataset().MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+
+	for b.Loop() {
+		var ds Dataset
+		if err := ds.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeJSON(b *testing.B) {
+	ds := benchmarkDataset()
+
+	for b.Loop() {
+		if _, err := json.Marshal(ds); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeBinary(b *testing.B) {
+	ds := benchmarkDataset()
+
+	for b.Loop() {
+		if _, err := ds.MarshalBinary(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
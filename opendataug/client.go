@@ -1,32 +1,77 @@
 package opendataug
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 )
 
 var baseURL = "https://api.opendataug.com/v1"
 
+// defaultMaxConcurrency bounds the number of in-flight requests issued by
+// methods that fan out over many parents.
+const defaultMaxConcurrency = 8
+
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
+	apiKey         string
+	httpClient     *http.Client
+	maxConcurrency int
+
+	countyCodes    codeIndex[County]
+	subcountyCodes codeIndex[Subcounty]
+	parishCodes    codeIndex[Parish]
+	villageCodes   codeIndex[Village]
+}
+
+// concurrencyLimit returns the number of concurrent requests api allows,
+// which is the default for backends other than Client
+func concurrencyLimit(api API) int {
+	if c, ok := api.(*Client); ok {
+		return c.maxConcurrency
+	}
+	return defaultMaxConcurrency
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithMaxConcurrency sets the maximum number of concurrent requests used by
+// methods that crawl the hierarchy. Values below 1 are ignored.
+func WithMaxConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxConcurrency = n
+		}
+	}
 }
 
-func NewClient(apiKey string) *Client {
-	return &Client{
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
 		apiKey: apiKey,
 		httpClient: &http.Client{
 			Timeout: time.Second * 30,
 		},
+		maxConcurrency: defaultMaxConcurrency,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 func (c *Client) doRequest(method, path string, v interface{}) error {
+	return c.doRequestContext(context.Background(), method, path, v)
+}
+
+func (c *Client) doRequestContext(ctx context.Context, method, path string, v interface{}) error {
 	url := fmt.Sprintf("%s%s", baseURL, path)
 
-	req, err := http.NewRequest(method, url, nil)
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return err
 	}
@@ -40,15 +85,41 @@ func (c *Client) doRequest(method, path string, v interface{}) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	// The body is not required to be an object, so a failure here only
+	// means there is no error message to report.
+	_ = json.Unmarshal(body, &apiErr)
+
+	if resp.StatusCode != http.StatusOK || apiErr.Error != "" {
+		return &APIError{StatusCode: resp.StatusCode, Message: apiErr.Error}
 	}
 
 	if v != nil {
-		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		if err := json.Unmarshal(body, v); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// getList retrieves the list of items served at path
+func getList[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var response struct {
+		Data []T `json:"data"`
+	}
+
+	err := c.doRequestContext(ctx, http.MethodGet, path, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Data, nil
+}
@@ -95,3 +95,32 @@ func TestDoRequestInvalidJSON(t *testing.T) {
 		t.Error("Expected an error for invalid JSON, got nil")
 	}
 }
+
+func TestDoRequestErrorMessage(t *testing.T) {
+	server, client := TestServer(t, "/test", `{"error": "Not found"}`)
+	defer server.Close()
+
+	err := client.doRequest(http.MethodGet, "/test", nil)
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if apiErr.Message != "Not found" {
+		t.Errorf("Expected message to be Not found, got %s", apiErr.Message)
+	}
+}
+
+func TestNewClientWithMaxConcurrency(t *testing.T) {
+	client := NewClient("test-api-key", WithMaxConcurrency(3))
+
+	if client.maxConcurrency != 3 {
+		t.Errorf("Expected maxConcurrency to be 3, got %d", client.maxConcurrency)
+	}
+
+	client = NewClient("test-api-key", WithMaxConcurrency(0))
+
+	if client.maxConcurrency != defaultMaxConcurrency {
+		t.Errorf("Expected maxConcurrency to be %d, got %d", defaultMaxConcurrency, client.maxConcurrency)
+	}
+}
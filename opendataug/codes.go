@@ -0,0 +1,192 @@
+package opendataug
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// The code lookups below ask the server to filter by code and then filter the
+// response again on the client, so they behave the same whether or not the
+// server honours the code parameter. When an unscoped lookup finds that the
+// server ignored the parameter and returned other codes, the Client indexes
+// that response by code and answers later unscoped lookups for the level from
+// the index, without downloading the level again. ClearCodeIndexes drops the
+// indexes.
+//
+// Codes are only unique within a parent; the unscoped lookups return
+// ErrAmbiguousCode when a code is reused, in which case the lookups scoped to
+// a parent should be used instead.
+
+// GetCountyByCode retrieves a specific county by its code
+func (c *Client) GetCountyByCode(ctx context.Context, code string) (*County, error) {
+	return findByCode(ctx, c, &c.countyCodes, "/counties", "county", code, func(county County) string {
+		return county.Code
+	})
+}
+
+// GetCountyByCodeInDistrict retrieves a county by its code within a specific district
+func (c *Client) GetCountyByCodeInDistrict(ctx context.Context, districtID DistrictID, code string) (*County, error) {
+	path := fmt.Sprintf("/districts/%s/counties", districtID)
+	return findByCode(ctx, c, nil, path, "county", code, func(county County) string {
+		return county.Code
+	})
+}
+
+// GetSubcountyByCode retrieves a specific subcounty by its code
+func (c *Client) GetSubcountyByCode(ctx context.Context, code string) (*Subcounty, error) {
+	return findByCode(ctx, c, &c.subcountyCodes, "/subcounties", "subcounty", code, func(subcounty Subcounty) string {
+		return subcounty.Code
+	})
+}
+
+// GetSubcountyByCodeInCounty retrieves a subcounty by its code within a specific county
+func (c *Client) GetSubcountyByCodeInCounty(ctx context.Context, countyID CountyID, code string) (*Subcounty, error) {
+	path := fmt.Sprintf("/counties/%s/subcounties", countyID)
+	return findByCode(ctx, c, nil, path, "subcounty", code, func(subcounty Subcounty) string {
+		return subcounty.Code
+	})
+}
+
+// GetParishByCode retrieves a specific parish by its code
+func (c *Client) GetParishByCode(ctx context.Context, code string) (*Parish, error) {
+	return findByCode(ctx, c, &c.parishCodes, "/parishes", "parish", code, func(parish Parish) string {
+		return parish.Code
+	})
+}
+
+// GetParishByCodeInSubcounty retrieves a parish by its code within a specific subcounty
+func (c *Client) GetParishByCodeInSubcounty(ctx context.Context, subcountyID SubcountyID, code string) (*Parish, error) {
+	path := fmt.Sprintf("/subcounties/%s/parishes", subcountyID)
+	return findByCode(ctx, c, nil, path, "parish", code, func(parish Parish) string {
+		return parish.Code
+	})
+}
+
+// GetVillageByCode retrieves a specific village by its code
+func (c *Client) GetVillageByCode(ctx context.Context, code string) (*Village, error) {
+	return findByCode(ctx, c, &c.villageCodes, "/villages", "village", code, func(village Village) string {
+		return village.Code
+	})
+}
+
+// GetVillageByCodeInParish retrieves a village by its code within a specific parish
+func (c *Client) GetVillageByCodeInParish(ctx context.Context, parishID ParishID, code string) (*Village, error) {
+	path := fmt.Sprintf("/parishes/%s/villages", parishID)
+	return findByCode(ctx, c, nil, path, "village", code, func(village Village) string {
+		return village.Code
+	})
+}
+
+// ClearCodeIndexes drops the code indexes built by unscoped code lookups, so
+// that the next lookup at each level asks the server again
+func (c *Client) ClearCodeIndexes() {
+	c.countyCodes.reset()
+	c.subcountyCodes.reset()
+	c.parishCodes.reset()
+	c.villageCodes.reset()
+}
+
+// findByCode looks up code at path. When index is not nil it is used instead
+// of a request once built, and built from the response when the server turns
+// out to ignore the code parameter.
+func findByCode[T any](ctx context.Context, c *Client, index *codeIndex[T], path, kind, code string, codeOf func(T) string) (*T, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, fmt.Errorf("%w: empty %s code", ErrNotFound, kind)
+	}
+
+	if index != nil {
+		if items, ok := index.lookup(code); ok {
+			return matchCode(items, kind, code, codeOf)
+		}
+	}
+
+	query := url.Values{"code": {code}}
+	items, err := getList[T](ctx, c, path+"?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if index != nil {
+		for _, item := range items {
+			if !sameCode(codeOf(item), code) {
+				index.build(items, codeOf)
+				break
+			}
+		}
+	}
+
+	return matchCode(items, kind, code, codeOf)
+}
+
+// codeIndex holds the units of a level grouped by code. It is empty until
+// built.
+type codeIndex[T any] struct {
+	mu     sync.RWMutex
+	byCode map[string][]T
+}
+
+// lookup returns the units with code, and whether the index has been built
+func (x *codeIndex[T]) lookup(code string) ([]T, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if x.byCode == nil {
+		return nil, false
+	}
+	return x.byCode[codeKey(code)], true
+}
+
+func (x *codeIndex[T]) build(items []T, codeOf func(T) string) {
+	byCode := make(map[string][]T)
+	for _, item := range items {
+		key := codeKey(codeOf(item))
+		byCode[key] = append(byCode[key], item)
+	}
+
+	x.mu.Lock()
+	x.byCode = byCode
+	x.mu.Unlock()
+}
+
+func (x *codeIndex[T]) reset() {
+	x.mu.Lock()
+	x.byCode = nil
+	x.mu.Unlock()
+}
+
+func codeKey(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+func sameCode(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// matchCode returns the only item in items with the given code
+func matchCode[T any](items []T, kind, code string, codeOf func(T) string) (*T, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, fmt.Errorf("%w: empty %s code", ErrNotFound, kind)
+	}
+
+	var match *T
+	for i := range items {
+		if !sameCode(codeOf(items[i]), code) {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("%w: %s code %q", ErrAmbiguousCode, kind, code)
+		}
+		match = &items[i]
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s with code %q", ErrNotFound, kind, code)
+	}
+
+	return match, nil
+}
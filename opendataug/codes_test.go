@@ -0,0 +1,203 @@
+package opendataug
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetCountyByCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/counties" {
+			t.Errorf("Expected request to /counties, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("code") != "KWP" {
+			t.Errorf("Expected code query to be KWP, got %s", r.URL.Query().Get("code"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"data": [
+				{"id": "county-2", "name": "Kawempe", "code": "KWP", "district_id": "district-1"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	originalBaseURL := baseURL
+	baseURL = server.URL
+	defer func() { baseURL = originalBaseURL }()
+
+	client := NewClient("test-api-key")
+
+	county, err := client.GetCountyByCode(context.Background(), "KWP")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := &County{ID: "county-2", Name: "Kawempe", Code: "KWP", DistrictID: "district-1"}
+	if !reflect.DeepEqual(county, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, county)
+	}
+}
+
+func TestGetVillageByCode(t *testing.T) {
+	// The server ignores the code parameter and returns every village, so the
+	// lookup has to filter on the client.
+	response := `{
+		"data": [
+			{"id": "village-1", "name": "Kiwatule A", "code": "001", "parish_id": "parish-1"},
+			{"id": "village-2", "name": "Kiwatule B", "code": "002", "parish_id": "parish-1"},
+			{"id": "village-3", "name": "Kazo Central", "code": "001", "parish_id": "parish-2"}
+		]
+	}`
+
+	tests := []struct {
+		name           string
+		code           string
+		expectedResult *Village
+		expectedError  error
+	}{
+		{
+			name:           "Unique code",
+			code:           "002",
+			expectedResult: &Village{ID: "village-2", Name: "Kiwatule B", Code: "002", ParishID: "parish-1"},
+		},
+		{
+			name:          "Ambiguous code",
+			code:          "001",
+			expectedError: ErrAmbiguousCode,
+		},
+		{
+			name:          "Unknown code",
+			code:          "999",
+			expectedError: ErrNotFound,
+		},
+		{
+			name:          "Empty code",
+			code:          " ",
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server, client := TestServer(t, "/villages", response)
+			defer server.Close()
+
+			village, err := client.GetVillageByCode(context.Background(), tc.code)
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+
+			if !reflect.DeepEqual(village, tc.expectedResult) {
+				t.Errorf("Expected %+v, got %+v", tc.expectedResult, village)
+			}
+		})
+	}
+}
+
+func TestGetVillageByCodeInParish(t *testing.T) {
+	server, client := TestServer(t, "/parishes/parish-2/villages", `{
+		"data": [
+			{"id": "village-3", "name": "Kazo Central", "code": "001", "parish_id": "parish-2"}
+		]
+	}`)
+	defer server.Close()
+
+	village, err := client.GetVillageByCodeInParish(context.Background(), "parish-2", "001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if village.ID != "village-3" {
+		t.Errorf("Expected village-3, got %s", village.ID)
+	}
+}
+
+func TestGetVillageByCodeIndex(t *testing.T) {
+	// The server ignores the code parameter, so the first lookup indexes the
+	// whole level and later lookups are answered from the index
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"data": [
+				{"id": "village-1", "name": "Kiwatule A", "code": "001", "parish_id": "parish-1"},
+				{"id": "village-2", "name": "Kiwatule B", "code": "002", "parish_id": "parish-1"},
+				{"id": "village-3", "name": "Kazo Central", "code": "001", "parish_id": "parish-2"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	originalBaseURL := baseURL
+	baseURL = server.URL
+	defer func() { baseURL = originalBaseURL }()
+
+	client := NewClient("test-api-key")
+	ctx := context.Background()
+
+	village, err := client.GetVillageByCode(ctx, "002")
+	if err != nil || village.ID != "village-2" {
+		t.Errorf("Expected village-2, got %+v, %v", village, err)
+	}
+	if _, err := client.GetVillageByCode(ctx, "001"); !errors.Is(err, ErrAmbiguousCode) {
+		t.Errorf("Expected ErrAmbiguousCode, got %v", err)
+	}
+	if _, err := client.GetVillageByCode(ctx, "999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+
+	// Scoped lookups and other levels do not use the village index
+	if _, err := client.GetVillageByCodeInParish(ctx, "parish-1", "002"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+
+	client.ClearCodeIndexes()
+	if _, err := client.GetVillageByCode(ctx, "002"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected the level to be fetched again after clearing, got %d requests", requests)
+	}
+}
+
+func TestGetCountyByCodeFilteredServer(t *testing.T) {
+	// A server that honours the code parameter is asked every time
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"data": [{"id": "county-2", "name": "Kawempe", "code": "KWP", "district_id": "district-1"}]}`))
+	}))
+	defer server.Close()
+
+	originalBaseURL := baseURL
+	baseURL = server.URL
+	defer func() { baseURL = originalBaseURL }()
+
+	client := NewClient("test-api-key")
+	for range 2 {
+		if _, err := client.GetCountyByCode(context.Background(), "kwp"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
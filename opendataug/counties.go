@@ -1,32 +1,34 @@
 package opendataug
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
 
 // GetCounties retrieves all counties
 func (c *Client) GetCounties() ([]County, error) {
-	var response struct {
-		Data []County `json:"data"`
-	}
-
-	err := c.doRequest(http.MethodGet, "/counties", &response)
-	if err != nil {
-		return nil, err
-	}
+	return c.GetCountiesContext(context.Background())
+}
 
-	return response.Data, nil
+// GetCountiesContext retrieves all counties using ctx
+func (c *Client) GetCountiesContext(ctx context.Context) ([]County, error) {
+	return getList[County](ctx, c, "/counties")
 }
 
 // GetCounty retrieves a specific county by ID
-func (c *Client) GetCounty(id string) (*County, error) {
+func (c *Client) GetCounty(id CountyID) (*County, error) {
+	return c.GetCountyContext(context.Background(), id)
+}
+
+// GetCountyContext retrieves a specific county by ID using ctx
+func (c *Client) GetCountyContext(ctx context.Context, id CountyID) (*County, error) {
 	var response struct {
 		Data County `json:"data"`
 	}
 
 	path := fmt.Sprintf("/counties/%s", id)
-	err := c.doRequest(http.MethodGet, path, &response)
+	err := c.doRequestContext(ctx, http.MethodGet, path, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -35,16 +37,12 @@ func (c *Client) GetCounty(id string) (*County, error) {
 }
 
 // GetCountiesByDistrict retrieves all counties in a specific district
-func (c *Client) GetCountiesByDistrict(districtID string) ([]County, error) {
-	var response struct {
-		Data []County `json:"data"`
-	}
+func (c *Client) GetCountiesByDistrict(districtID DistrictID) ([]County, error) {
+	return c.GetCountiesByDistrictContext(context.Background(), districtID)
+}
 
+// GetCountiesByDistrictContext retrieves all counties in a specific district using ctx
+func (c *Client) GetCountiesByDistrictContext(ctx context.Context, districtID DistrictID) ([]County, error) {
 	path := fmt.Sprintf("/districts/%s/counties", districtID)
-	err := c.doRequest(http.MethodGet, path, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return response.Data, nil
+	return getList[County](ctx, c, path)
 }
@@ -54,7 +54,7 @@ func TestGetCounties(t *testing.T) {
 func TestGetCounty(t *testing.T) {
 	tests := []struct {
 		name           string
-		countyID       string
+		countyID       CountyID
 		expectedPath   string
 		response       string
 		expectedResult *County
@@ -117,7 +117,7 @@ func TestGetCounty(t *testing.T) {
 func TestGetCountiesByDistrict(t *testing.T) {
 	tests := []struct {
 		name           string
-		districtID     string
+		districtID     DistrictID
 		expectedPath   string
 		response       string
 		expectedResult []County
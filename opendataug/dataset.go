@@ -0,0 +1,57 @@
+package opendataug
+
+import "context"
+
+// Dataset holds every unit of the administrative hierarchy as returned by
+// the list endpoints
+type Dataset struct {
+	Districts   []District  `json:"districts"`
+	Counties    []County    `json:"counties"`
+	Subcounties []Subcounty `json:"subcounties"`
+	Parishes    []Parish    `json:"parishes"`
+	Villages    []Village   `json:"villages"`
+}
+
+// LoadDataset retrieves all five levels of the hierarchy concurrently
+func LoadDataset(ctx context.Context, api API) (*Dataset, error) {
+	ds := &Dataset{}
+
+	err := runAll(ctx,
+		func(ctx context.Context) (err error) {
+			ds.Districts, err = api.GetDistrictsContext(ctx)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			ds.Counties, err = api.GetCountiesContext(ctx)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			ds.Subcounties, err = api.GetSubcountiesContext(ctx)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			ds.Parishes, err = api.GetParishesContext(ctx)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			ds.Villages, err = api.GetVillagesContext(ctx)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ds, nil
+}
+
+// units returns the units of every level of ds
+func (ds *Dataset) units() map[Level][]AdminUnit {
+	return map[Level][]AdminUnit{
+		LevelDistrict:  units(ds.Districts),
+		LevelCounty:    units(ds.Counties),
+		LevelSubcounty: units(ds.Subcounties),
+		LevelParish:    units(ds.Parishes),
+		LevelVillage:   units(ds.Villages),
+	}
+}
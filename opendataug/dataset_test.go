@@ -0,0 +1,23 @@
+package opendataug
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestLoadDataset(t *testing.T) {
+	expected := testDataset()
+
+	server, client := TestRoutesServer(t, datasetRoutes(t, expected))
+	defer server.Close()
+
+	ds, err := LoadDataset(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(ds, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, ds)
+	}
+}
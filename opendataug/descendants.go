@@ -0,0 +1,137 @@
+package opendataug
+
+import (
+	"context"
+	"fmt"
+)
+
+// The methods in this file return every unit of one level below an ancestor
+// more than one level up. Each first tries a direct endpoint and, if the
+// server does not provide it, crawls the hierarchy one level at a time with
+// at most the client's configured number of concurrent requests.
+
+// GetSubcountiesByDistrict retrieves all subcounties in a specific district
+func (c *Client) GetSubcountiesByDistrict(ctx context.Context, districtID DistrictID) ([]Subcounty, error) {
+	path := fmt.Sprintf("/districts/%s/subcounties", districtID)
+	subcounties, err := getList[Subcounty](ctx, c, path)
+	if !isNotFound(err) {
+		return subcounties, err
+	}
+
+	return c.crawlSubcountiesByDistrict(ctx, districtID)
+}
+
+// GetParishesByDistrict retrieves all parishes in a specific district
+func (c *Client) GetParishesByDistrict(ctx context.Context, districtID DistrictID) ([]Parish, error) {
+	path := fmt.Sprintf("/districts/%s/parishes", districtID)
+	parishes, err := getList[Parish](ctx, c, path)
+	if !isNotFound(err) {
+		return parishes, err
+	}
+
+	return c.crawlParishesByDistrict(ctx, districtID)
+}
+
+// GetParishesByCounty retrieves all parishes in a specific county
+func (c *Client) GetParishesByCounty(ctx context.Context, countyID CountyID) ([]Parish, error) {
+	path := fmt.Sprintf("/counties/%s/parishes", countyID)
+	parishes, err := getList[Parish](ctx, c, path)
+	if !isNotFound(err) {
+		return parishes, err
+	}
+
+	return c.crawlParishesByCounty(ctx, countyID)
+}
+
+// GetVillagesByDistrict retrieves all villages in a specific district
+func (c *Client) GetVillagesByDistrict(ctx context.Context, districtID DistrictID) ([]Village, error) {
+	path := fmt.Sprintf("/districts/%s/villages", districtID)
+	villages, err := getList[Village](ctx, c, path)
+	if !isNotFound(err) {
+		return villages, err
+	}
+
+	parishes, err := c.crawlParishesByDistrict(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.villagesInParishes(ctx, parishes)
+}
+
+// GetVillagesByCounty retrieves all villages in a specific county
+func (c *Client) GetVillagesByCounty(ctx context.Context, countyID CountyID) ([]Village, error) {
+	path := fmt.Sprintf("/counties/%s/villages", countyID)
+	villages, err := getList[Village](ctx, c, path)
+	if !isNotFound(err) {
+		return villages, err
+	}
+
+	parishes, err := c.crawlParishesByCounty(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.villagesInParishes(ctx, parishes)
+}
+
+// GetVillagesBySubcounty retrieves all villages in a specific subcounty
+func (c *Client) GetVillagesBySubcounty(ctx context.Context, subcountyID SubcountyID) ([]Village, error) {
+	path := fmt.Sprintf("/subcounties/%s/villages", subcountyID)
+	villages, err := getList[Village](ctx, c, path)
+	if !isNotFound(err) {
+		return villages, err
+	}
+
+	parishes, err := c.GetParishesBySubcountyContext(ctx, subcountyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.villagesInParishes(ctx, parishes)
+}
+
+// The crawl helpers below only use the endpoints for direct children. Once a
+// direct descendants endpoint has been found missing, the others are assumed
+// to be missing too, so they are not tried again.
+
+func (c *Client) crawlSubcountiesByDistrict(ctx context.Context, districtID DistrictID) ([]Subcounty, error) {
+	counties, err := c.GetCountiesByDistrictContext(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+
+	return fanOut(ctx, c.maxConcurrency, counties, func(ctx context.Context, county County) ([]Subcounty, error) {
+		return c.GetSubcountiesByCountyContext(ctx, county.ID)
+	})
+}
+
+func (c *Client) crawlParishesByDistrict(ctx context.Context, districtID DistrictID) ([]Parish, error) {
+	subcounties, err := c.crawlSubcountiesByDistrict(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.parishesInSubcounties(ctx, subcounties)
+}
+
+func (c *Client) crawlParishesByCounty(ctx context.Context, countyID CountyID) ([]Parish, error) {
+	subcounties, err := c.GetSubcountiesByCountyContext(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.parishesInSubcounties(ctx, subcounties)
+}
+
+func (c *Client) parishesInSubcounties(ctx context.Context, subcounties []Subcounty) ([]Parish, error) {
+	return fanOut(ctx, c.maxConcurrency, subcounties, func(ctx context.Context, subcounty Subcounty) ([]Parish, error) {
+		return c.GetParishesBySubcountyContext(ctx, subcounty.ID)
+	})
+}
+
+func (c *Client) villagesInParishes(ctx context.Context, parishes []Parish) ([]Village, error) {
+	return fanOut(ctx, c.maxConcurrency, parishes, func(ctx context.Context, parish Parish) ([]Village, error) {
+		return c.GetVillagesByParishContext(ctx, parish.ID)
+	})
+}
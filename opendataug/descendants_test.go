@@ -0,0 +1,205 @@
+package opendataug
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestGetVillagesByDistrictDirect(t *testing.T) {
+	server, client := TestRoutesServer(t, map[string]string{
+		"/districts/district-1/villages": `{
+			"data": [
+				{"id": "village-1", "name": "Kiwatule", "code": "KWT", "parish_id": "parish-1"}
+			]
+		}`,
+	})
+	defer server.Close()
+
+	villages, err := client.GetVillagesByDistrict(context.Background(), "district-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []Village{
+		{ID: "village-1", Name: "Kiwatule", Code: "KWT", ParishID: "parish-1"},
+	}
+
+	if !reflect.DeepEqual(villages, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, villages)
+	}
+}
+
+func TestGetVillagesByDistrictCrawl(t *testing.T) {
+	server, client := TestRoutesServer(t, map[string]string{
+		"/districts/district-1/counties": `{
+			"data": [
+				{"id": "county-1", "name": "Nakawa", "district_id": "district-1"},
+				{"id": "county-2", "name": "Kawempe", "district_id": "district-1"}
+			]
+		}`,
+		"/counties/county-1/subcounties": `{
+			"data": [
+				{"id": "subcounty-1", "name": "Nakawa Division", "county_id": "county-1"}
+			]
+		}`,
+		"/counties/county-2/subcounties": `{
+			"data": [
+				{"id": "subcounty-2", "name": "Kawempe Division", "county_id": "county-2"}
+			]
+		}`,
+		"/subcounties/subcounty-1/parishes": `{
+			"data": [
+				{"id": "parish-1", "name": "Kiwatule", "subcounty_id": "subcounty-1"},
+				{"id": "parish-2", "name": "Ntinda", "subcounty_id": "subcounty-1"}
+			]
+		}`,
+		"/subcounties/subcounty-2/parishes": `{
+			"data": [
+				{"id": "parish-3", "name": "Kazo", "subcounty_id": "subcounty-2"}
+			]
+		}`,
+		"/parishes/parish-1/villages": `{
+			"data": [
+				{"id": "village-1", "name": "Kiwatule A", "parish_id": "parish-1"}
+			]
+		}`,
+		"/parishes/parish-2/villages": `{
+			"data": []
+		}`,
+		"/parishes/parish-3/villages": `{
+			"data": [
+				{"id": "village-2", "name": "Kazo Central", "parish_id": "parish-3"},
+				{"id": "village-3", "name": "Kazo Angola", "parish_id": "parish-3"}
+			]
+		}`,
+	})
+	defer server.Close()
+
+	villages, err := client.GetVillagesByDistrict(context.Background(), "district-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []Village{
+		{ID: "village-1", Name: "Kiwatule A", ParishID: "parish-1"},
+		{ID: "village-2", Name: "Kazo Central", ParishID: "parish-3"},
+		{ID: "village-3", Name: "Kazo Angola", ParishID: "parish-3"},
+	}
+
+	if !reflect.DeepEqual(villages, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, villages)
+	}
+}
+
+func TestGetParishesByCountyCrawl(t *testing.T) {
+	server, client := TestRoutesServer(t, map[string]string{
+		"/counties/county-1/subcounties": `{
+			"data": [
+				{"id": "subcounty-1", "name": "Nakawa Division", "county_id": "county-1"}
+			]
+		}`,
+		"/subcounties/subcounty-1/parishes": `{
+			"data": [
+				{"id": "parish-1", "name": "Kiwatule", "subcounty_id": "subcounty-1"}
+			]
+		}`,
+	})
+	defer server.Close()
+
+	parishes, err := client.GetParishesByCounty(context.Background(), "county-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []Parish{
+		{ID: "parish-1", Name: "Kiwatule", SubcountyID: "subcounty-1"},
+	}
+
+	if !reflect.DeepEqual(parishes, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, parishes)
+	}
+}
+
+func TestGetSubcountiesByDistrictNotFound(t *testing.T) {
+	server, client := TestRoutesServer(t, map[string]string{})
+	defer server.Close()
+
+	_, err := client.GetSubcountiesByDistrict(context.Background(), "invalid-id")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if apiErr.StatusCode != 404 {
+		t.Errorf("Expected status code 404, got %d", apiErr.StatusCode)
+	}
+}
+
+func TestGetVillagesBySubcountyCancelled(t *testing.T) {
+	server, client := TestRoutesServer(t, map[string]string{
+		"/subcounties/subcounty-1/villages": `{"data": []}`,
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetVillagesBySubcounty(ctx, "subcounty-1")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGetVillagesByDistrictCrawlRequests(t *testing.T) {
+	routes := map[string]string{
+		"/districts/district-1/counties":    `{"data": [{"id": "county-1", "district_id": "district-1"}]}`,
+		"/counties/county-1/subcounties":    `{"data": [{"id": "subcounty-1", "county_id": "county-1"}]}`,
+		"/subcounties/subcounty-1/parishes": `{"data": [{"id": "parish-1", "subcounty_id": "subcounty-1"}]}`,
+		"/parishes/parish-1/villages":       `{"data": [{"id": "village-1", "parish_id": "parish-1"}]}`,
+	}
+
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+
+		response, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "Not found"}`))
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+	baseURL = server.URL
+
+	villages, err := NewClient("test-api-key").GetVillagesByDistrict(context.Background(), "district-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(villages) != 1 {
+		t.Errorf("Expected 1 village, got %+v", villages)
+	}
+
+	// Only the direct villages endpoint is tried before crawling
+	expected := []string{
+		"/districts/district-1/villages",
+		"/districts/district-1/counties",
+		"/counties/county-1/subcounties",
+		"/subcounties/subcounty-1/parishes",
+		"/parishes/parish-1/villages",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+}
@@ -0,0 +1,191 @@
+package opendataug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ChangeKind classifies a change between two datasets
+type ChangeKind string
+
+const (
+	// ChangeAdded is a unit present only in the new dataset
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved is a unit present only in the old dataset
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeRenamed is a unit whose name changed
+	ChangeRenamed ChangeKind = "renamed"
+	// ChangeRecoded is a unit whose code changed
+	ChangeRecoded ChangeKind = "recoded"
+	// ChangeReparented is a unit moved to another parent, or a district
+	// moved to another region
+	ChangeReparented ChangeKind = "reparented"
+)
+
+// changeKinds lists the kinds in the order they are summarised
+var changeKinds = []ChangeKind{ChangeAdded, ChangeRemoved, ChangeRenamed, ChangeRecoded, ChangeReparented}
+
+// Change is a single difference between two datasets. Old and New hold the
+// changed name, code or parent ID and are empty for added and removed units.
+type Change struct {
+	Kind  ChangeKind `json:"kind"`
+	Level Level      `json:"level"`
+	ID    string     `json:"id"`
+	Name  string     `json:"name"`
+	Old   string     `json:"old,omitempty"`
+	New   string     `json:"new,omitempty"`
+}
+
+// Diff lists the changes between two datasets
+type Diff struct {
+	Changes []Change `json:"changes"`
+}
+
+// DiffDatasets compares two datasets unit by unit, matching units by level
+// and ID. A unit that was renamed and moved yields one change of each kind.
+// Changes are ordered by level; within a level, changes to units of after
+// come in their order in after, followed by removals in their order in
+// before. When an ID occurs more than once in a level only its first unit is
+// compared.
+func DiffDatasets(before, after *Dataset) *Diff {
+	d := &Diff{Changes: []Change{}}
+
+	beforeLevels, afterLevels := before.units(), after.units()
+	for _, level := range Levels {
+		beforeByID := firstByID(beforeLevels[level])
+		afterByID := firstByID(afterLevels[level])
+
+		seen := make(map[string]bool)
+		for _, u := range afterLevels[level] {
+			if seen[u.UnitID()] {
+				continue
+			}
+			seen[u.UnitID()] = true
+
+			prev, ok := beforeByID[u.UnitID()]
+			if !ok {
+				d.Changes = append(d.Changes, Change{Kind: ChangeAdded, Level: level, ID: u.UnitID(), Name: u.UnitName()})
+				continue
+			}
+			d.Changes = append(d.Changes, unitChanges(prev, u)...)
+		}
+
+		seen = make(map[string]bool)
+		for _, u := range beforeLevels[level] {
+			if _, ok := afterByID[u.UnitID()]; ok || seen[u.UnitID()] {
+				continue
+			}
+			seen[u.UnitID()] = true
+			d.Changes = append(d.Changes, Change{Kind: ChangeRemoved, Level: level, ID: u.UnitID(), Name: u.UnitName()})
+		}
+	}
+
+	return d
+}
+
+// Empty reports whether the datasets were identical in every compared field
+func (d *Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Counts returns the number of changes of each kind
+func (d *Diff) Counts() map[ChangeKind]int {
+	counts := make(map[ChangeKind]int)
+	for _, c := range d.Changes {
+		counts[c.Kind]++
+	}
+	return counts
+}
+
+// WriteJSON writes the diff to w as indented JSON
+func (d *Diff) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// Changelog returns the diff as a human-readable list of changes
+func (d *Diff) Changelog() string {
+	var b strings.Builder
+
+	if d.Empty() {
+		b.WriteString("No changes\n")
+		return b.String()
+	}
+
+	counts := d.Counts()
+	var parts []string
+	for _, kind := range changeKinds {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	fmt.Fprintf(&b, "%d changes: %s\n", len(d.Changes), strings.Join(parts, ", "))
+
+	level := LevelUnknown
+	for _, c := range d.Changes {
+		if c.Level != level {
+			level = c.Level
+			fmt.Fprintf(&b, "\n%s:\n", level)
+		}
+
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "  + added %q (%s)\n", c.Name, c.ID)
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "  - removed %q (%s)\n", c.Name, c.ID)
+		case ChangeRenamed:
+			fmt.Fprintf(&b, "  ~ renamed %s from %q to %q\n", c.ID, c.Old, c.New)
+		case ChangeRecoded:
+			fmt.Fprintf(&b, "  ~ recoded %q (%s) from %q to %q\n", c.Name, c.ID, c.Old, c.New)
+		case ChangeReparented:
+			fmt.Fprintf(&b, "  ~ moved %q (%s) from %s to %s\n", c.Name, c.ID, c.Old, c.New)
+		}
+	}
+
+	return b.String()
+}
+
+func unitChanges(before, after AdminUnit) []Change {
+	var changes []Change
+	change := func(kind ChangeKind, from, to string) {
+		if from == to {
+			return
+		}
+		changes = append(changes, Change{
+			Kind:  kind,
+			Level: after.Level(),
+			ID:    after.UnitID(),
+			Name:  after.UnitName(),
+			Old:   from,
+			New:   to,
+		})
+	}
+
+	change(ChangeRenamed, before.UnitName(), after.UnitName())
+	change(ChangeRecoded, before.UnitCode(), after.UnitCode())
+	change(ChangeReparented, diffParentID(before), diffParentID(after))
+
+	return changes
+}
+
+// diffParentID returns the parent ID of u, using the region of districts
+func diffParentID(u AdminUnit) string {
+	if d, ok := u.(District); ok {
+		return d.RegionID
+	}
+	return u.ParentID()
+}
+
+// firstByID indexes units by ID, keeping the first of any duplicates
+func firstByID(units []AdminUnit) map[string]AdminUnit {
+	index := make(map[string]AdminUnit, len(units))
+	for _, u := range units {
+		if _, ok := index[u.UnitID()]; !ok {
+			index[u.UnitID()] = u
+		}
+	}
+	return index
+}
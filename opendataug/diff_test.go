@@ -0,0 +1,83 @@
+package opendataug
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffDatasets(t *testing.T) {
+	before := testDataset()
+	after := testDataset()
+
+	after.Districts[1].RegionID = "region-2"
+	after.Counties[0].Name = "Nakawa East"
+	after.Counties[0].Code = "NKE"
+	after.Parishes[1].SubcountyID = "subcounty-2"
+	after.Villages = append(after.Villages[:2], after.Villages[3:]...)
+	after.Villages = append(after.Villages, Village{ID: "village-10", Name: "Kiwatule C", ParishID: "parish-1"})
+
+	d := DiffDatasets(before, after)
+
+	expected := []Change{
+		{Kind: ChangeReparented, Level: LevelDistrict, ID: "district-2", Name: "Wakiso", Old: "region-1", New: "region-2"},
+		{Kind: ChangeRenamed, Level: LevelCounty, ID: "county-1", Name: "Nakawa East", Old: "Nakawa", New: "Nakawa East"},
+		{Kind: ChangeRecoded, Level: LevelCounty, ID: "county-1", Name: "Nakawa East", Old: "NKW", New: "NKE"},
+		{Kind: ChangeReparented, Level: LevelParish, ID: "parish-2", Name: "Ntinda", Old: "subcounty-1", New: "subcounty-2"},
+		{Kind: ChangeAdded, Level: LevelVillage, ID: "village-10", Name: "Kiwatule C"},
+		{Kind: ChangeRemoved, Level: LevelVillage, ID: "village-3", Name: "Ntinda Central"},
+	}
+
+	if !reflect.DeepEqual(d.Changes, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, d.Changes)
+	}
+
+	counts := d.Counts()
+	if counts[ChangeReparented] != 2 || counts[ChangeAdded] != 1 {
+		t.Errorf("Expected 2 reparented and 1 added, got %v", counts)
+	}
+}
+
+func TestDiffDatasetsIdentical(t *testing.T) {
+	d := DiffDatasets(testDataset(), testDataset())
+	if !d.Empty() {
+		t.Errorf("Expected no changes, got %+v", d.Changes)
+	}
+	if d.Changelog() != "No changes\n" {
+		t.Errorf("Expected no changes, got %q", d.Changelog())
+	}
+}
+
+func TestDiffOutput(t *testing.T) {
+	before := testDataset()
+	after := testDataset()
+	after.Counties[0].Name = "Nakawa East"
+	after.Districts = after.Districts[:1]
+
+	d := DiffDatasets(before, after)
+
+	var buf bytes.Buffer
+	if err := d.WriteJSON(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var decoded Diff
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, d) {
+		t.Errorf("Expected %+v, got %+v", d, decoded)
+	}
+
+	log := d.Changelog()
+	for _, want := range []string{
+		"2 changes: 1 removed, 1 renamed",
+		"district:\n  - removed \"Wakiso\" (district-2)",
+		"county:\n  ~ renamed county-1 from \"Nakawa\" to \"Nakawa East\"",
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("Expected changelog to contain %q, got:\n%s", want, log)
+		}
+	}
+}
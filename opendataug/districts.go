@@ -1,32 +1,34 @@
 package opendataug
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
 
 // GetDistricts retrieves all districts
 func (c *Client) GetDistricts() ([]District, error) {
-	var response struct {
-		Data []District `json:"data"`
-	}
-
-	err := c.doRequest(http.MethodGet, "/districts", &response)
-	if err != nil {
-		return nil, err
-	}
+	return c.GetDistrictsContext(context.Background())
+}
 
-	return response.Data, nil
+// GetDistrictsContext retrieves all districts using ctx
+func (c *Client) GetDistrictsContext(ctx context.Context) ([]District, error) {
+	return getList[District](ctx, c, "/districts")
 }
 
 // GetDistrict retrieves a specific district by ID
-func (c *Client) GetDistrict(id string) (*District, error) {
+func (c *Client) GetDistrict(id DistrictID) (*District, error) {
+	return c.GetDistrictContext(context.Background(), id)
+}
+
+// GetDistrictContext retrieves a specific district by ID using ctx
+func (c *Client) GetDistrictContext(ctx context.Context, id DistrictID) (*District, error) {
 	var response struct {
 		Data District `json:"data"`
 	}
 
 	path := fmt.Sprintf("/districts/%s", id)
-	err := c.doRequest(http.MethodGet, path, &response)
+	err := c.doRequestContext(ctx, http.MethodGet, path, &response)
 	if err != nil {
 		return nil, err
 	}
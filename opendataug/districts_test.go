@@ -59,7 +59,7 @@ func TestGetDistricts(t *testing.T) {
 func TestGetDistrict(t *testing.T) {
 	tests := []struct {
 		name           string
-		districtID     string
+		districtID     DistrictID
 		expectedPath   string
 		response       string
 		expectedResult *District
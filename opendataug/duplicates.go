@@ -0,0 +1,188 @@
+package opendataug
+
+import (
+	"sort"
+	"strings"
+)
+
+// DuplicateKind classifies how alike the names of a group of siblings are
+type DuplicateKind string
+
+const (
+	// DuplicateExact is a group of siblings with identical names
+	DuplicateExact DuplicateKind = "exact"
+	// DuplicateWhitespaceCase is a group of siblings whose names only
+	// differ in case or whitespace, such as "Kiwatule" and "kiwatule "
+	DuplicateWhitespaceCase DuplicateKind = "whitespace_case"
+	// DuplicateFuzzy is a pair of siblings with similar names
+	DuplicateFuzzy DuplicateKind = "fuzzy"
+)
+
+// duplicateSeverity orders the kinds from the most to the least certain
+var duplicateSeverity = map[DuplicateKind]int{
+	DuplicateExact:          0,
+	DuplicateWhitespaceCase: 1,
+	DuplicateFuzzy:          2,
+}
+
+// DuplicateGroup is a set of units under the same parent that probably name
+// the same place
+type DuplicateGroup struct {
+	Kind     DuplicateKind `json:"kind"`
+	Level    Level         `json:"level"`
+	ParentID string        `json:"parent_id"`
+	Units    []AdminUnit   `json:"units"`
+	// Score is the NameSimilarity of the names, which is 1 for exact and
+	// whitespace or case duplicates
+	Score float64 `json:"score"`
+}
+
+// DuplicateOptions configure FindDuplicates
+type DuplicateOptions struct {
+	// MinScore is the lowest NameSimilarity reported as a fuzzy duplicate.
+	// Zero means DefaultMinScore; a value above 1 disables fuzzy matching.
+	MinScore float64
+}
+
+// FindDuplicates finds siblings with the same or similar names at every level
+// of ds. Districts are all treated as siblings. Groups are ranked for review:
+// exact duplicates first, then whitespace or case duplicates, then fuzzy
+// duplicates by descending score.
+func FindDuplicates(ds *Dataset, opts DuplicateOptions) []DuplicateGroup {
+	minScore := opts.MinScore
+	if minScore == 0 {
+		minScore = DefaultMinScore
+	}
+
+	var groups []DuplicateGroup
+	levels := ds.units()
+
+	for _, level := range Levels {
+		siblings := make(map[string][]AdminUnit)
+		var parents []string
+		for _, u := range levels[level] {
+			if _, ok := siblings[u.ParentID()]; !ok {
+				parents = append(parents, u.ParentID())
+			}
+			siblings[u.ParentID()] = append(siblings[u.ParentID()], u)
+		}
+
+		for _, parentID := range parents {
+			groups = append(groups, siblingDuplicates(level, parentID, siblings[parentID], minScore)...)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if duplicateSeverity[a.Kind] != duplicateSeverity[b.Kind] {
+			return duplicateSeverity[a.Kind] < duplicateSeverity[b.Kind]
+		}
+		return a.Score > b.Score
+	})
+
+	return groups
+}
+
+func siblingDuplicates(level Level, parentID string, siblings []AdminUnit, minScore float64) []DuplicateGroup {
+	var (
+		groups []DuplicateGroup
+		folded []string
+	)
+	byFolded := make(map[string][]AdminUnit)
+
+	for _, u := range siblings {
+		key := strings.ToLower(strings.Join(strings.Fields(u.UnitName()), " "))
+		if _, ok := byFolded[key]; !ok {
+			folded = append(folded, key)
+		}
+		byFolded[key] = append(byFolded[key], u)
+	}
+
+	for _, key := range folded {
+		units := byFolded[key]
+		if len(units) < 2 {
+			continue
+		}
+
+		kind := DuplicateExact
+		for _, u := range units[1:] {
+			if u.UnitName() != units[0].UnitName() {
+				kind = DuplicateWhitespaceCase
+				break
+			}
+		}
+		groups = append(groups, DuplicateGroup{
+			Kind:     kind,
+			Level:    level,
+			ParentID: parentID,
+			Units:    units,
+			Score:    1,
+		})
+	}
+
+	if minScore > 1 {
+		return groups
+	}
+
+	for i, a := range folded {
+		for _, b := range folded[i+1:] {
+			if designatorsDiffer(a, b) {
+				continue
+			}
+			score := NameSimilarity(a, b)
+			if score < minScore {
+				continue
+			}
+			groups = append(groups, DuplicateGroup{
+				Kind:     DuplicateFuzzy,
+				Level:    level,
+				ParentID: parentID,
+				Units:    []AdminUnit{byFolded[a][0], byFolded[b][0]},
+				Score:    score,
+			})
+		}
+	}
+
+	return groups
+}
+
+// designatorsDiffer reports whether a and b only differ in one short word such
+// as the letter, number or roman numeral in "Kiwatule A" and "Kiwatule B",
+// which sets siblings apart rather than misspelling them
+func designatorsDiffer(a, b string) bool {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	if len(wa) != len(wb) {
+		return false
+	}
+
+	diff := -1
+	for i := range wa {
+		if wa[i] == wb[i] {
+			continue
+		}
+		if diff >= 0 {
+			return false
+		}
+		diff = i
+	}
+
+	return diff >= 0 && isDesignator(wa[diff]) && isDesignator(wb[diff])
+}
+
+func isDesignator(word string) bool {
+	if len(word) <= 2 || isRomanNumeral(word) {
+		return true
+	}
+	for _, r := range word {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isRomanNumeral reports whether word is made up of the numerals I, V and X
+// in either case, which is as far as unit names count
+func isRomanNumeral(word string) bool {
+	return word != "" && strings.Trim(strings.ToUpper(word), "IVX") == ""
+}
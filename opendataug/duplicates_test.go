@@ -0,0 +1,102 @@
+package opendataug
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	ds := testDataset()
+	ds.Parishes = append(ds.Parishes,
+		Parish{ID: "parish-5", Name: "Kiwatule ", SubcountyID: "subcounty-1"},
+		Parish{ID: "parish-6", Name: "Ntinda", SubcountyID: "subcounty-1"},
+		Parish{ID: "parish-7", Name: "Kiwatule", SubcountyID: "subcounty-3"},
+	)
+	ds.Villages = append(ds.Villages,
+		Village{ID: "village-10", Name: "Kazo Centrall", ParishID: "parish-3"},
+	)
+	ds.Districts = append(ds.Districts, District{ID: "district-3", Name: "Kampaala"})
+
+	groups := FindDuplicates(ds, DuplicateOptions{})
+
+	type group struct {
+		Kind DuplicateKind
+		IDs  []string
+	}
+	var got []group
+	for _, g := range groups {
+		var ids []string
+		for _, u := range g.Units {
+			ids = append(ids, u.UnitID())
+		}
+		got = append(got, group{Kind: g.Kind, IDs: ids})
+	}
+
+	expected := []group{
+		{Kind: DuplicateExact, IDs: []string{"parish-2", "parish-6"}},
+		{Kind: DuplicateWhitespaceCase, IDs: []string{"parish-1", "parish-5"}},
+		{Kind: DuplicateFuzzy, IDs: []string{"village-4", "village-10"}},
+		{Kind: DuplicateFuzzy, IDs: []string{"district-1", "district-3"}},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	for _, g := range groups[2:] {
+		if g.Score < DefaultMinScore || g.Score >= 1 {
+			t.Errorf("Expected a fuzzy score in [%v, 1), got %v", DefaultMinScore, g.Score)
+		}
+	}
+
+	if groups[2].Score < groups[3].Score {
+		t.Errorf("Expected fuzzy groups by descending score, got %v then %v", groups[2].Score, groups[3].Score)
+	}
+
+	if groups[1].Level != LevelParish || groups[1].ParentID != "subcounty-1" {
+		t.Errorf("Expected the group to be under subcounty-1, got %+v", groups[1])
+	}
+}
+
+func TestFindDuplicatesWithoutFuzzy(t *testing.T) {
+	ds := testDataset()
+	ds.Districts = append(ds.Districts, District{ID: "district-3", Name: "Kampaala"})
+
+	if groups := FindDuplicates(ds, DuplicateOptions{MinScore: 2}); len(groups) != 0 {
+		t.Errorf("Expected no duplicates, got %+v", groups)
+	}
+
+	// Kiwatule A and Kiwatule B are told apart by their designators
+	if groups := FindDuplicates(testDataset(), DuplicateOptions{}); len(groups) != 0 {
+		t.Errorf("Expected no duplicates, got %+v", groups)
+	}
+}
+
+func TestFindDuplicatesDesignators(t *testing.T) {
+	tests := []struct {
+		name      string
+		villages  []string
+		duplicate bool
+	}{
+		{name: "Letters", villages: []string{"Kibuye A", "Kibuye B", "Kibuye C"}},
+		{name: "Numbers", villages: []string{"Kibuye 1", "Kibuye 2", "Kibuye 10"}},
+		{name: "Roman numerals", villages: []string{"Kibuye I", "Kibuye II", "Kibuye III"}},
+		{name: "Lower-case roman numerals", villages: []string{"Kibuye iv", "Kibuye viii", "Kibuye xii"}},
+		{name: "Misspelling", villages: []string{"Kibuye Central", "Kibuye Centrall"}, duplicate: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ds := &Dataset{}
+			for i, name := range tc.villages {
+				ds.Villages = append(ds.Villages, Village{ID: VillageID(fmt.Sprintf("village-%d", i+1)), Name: name, ParishID: "parish-1"})
+			}
+
+			groups := FindDuplicates(ds, DuplicateOptions{})
+			if got := len(groups) > 0; got != tc.duplicate {
+				t.Errorf("Expected duplicate to be %v, got %+v", tc.duplicate, groups)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Package embedded compiles a snapshot of the administrative hierarchy into
+// the binary, for small services that should not depend on the API at run
+// time.
+//
+// The snapshot is stored in dataset.bin in the binary format written by
+// opendataug.WriteBinary and decoded on first use. The copy in the repository
+// is an empty placeholder; run
+//
+//	OPENDATAUG_API_KEY=... go generate ./opendataug/embedded
+//
+// to replace it with the current data from the API before building.
+package embedded
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"sync"
+
+	"github.com/Open-Data-Uganda/opendataug-go/opendataug"
+)
+
+//go:generate go run ./internal/gen -o dataset.bin
+
+//go:embed dataset.bin
+var dataset []byte
+
+// load decodes the embedded dataset once and shares the result
+var load = sync.OnceValues(func() (*opendataug.OfflineClient, error) {
+	ds, err := decode(dataset)
+	if err != nil {
+		return nil, err
+	}
+	return opendataug.NewOfflineClient(ds), nil
+})
+
+// Client returns an offline client serving the embedded snapshot. The
+// snapshot is decoded on the first call and shared by every later call.
+func Client() (*opendataug.OfflineClient, error) {
+	return load()
+}
+
+// MustClient is like Client but panics if the embedded snapshot is corrupt
+func MustClient() *opendataug.OfflineClient {
+	c, err := Client()
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func decode(data []byte) (*opendataug.Dataset, error) {
+	ds, err := opendataug.ReadBinary(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("embedded: decoding snapshot: %w", err)
+	}
+	return ds, nil
+}
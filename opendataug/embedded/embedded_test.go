@@ -0,0 +1,54 @@
+package embedded
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Open-Data-Uganda/opendataug-go/opendataug"
+)
+
+func TestDecode(t *testing.T) {
+	ds := &opendataug.Dataset{
+		Districts:   []opendataug.District{{ID: "district-1", Name: "Kampala"}},
+		Counties:    []opendataug.County{{ID: "county-1", Name: "Nakawa", DistrictID: "district-1"}},
+		Subcounties: []opendataug.Subcounty{},
+		Parishes:    []opendataug.Parish{},
+		Villages:    []opendataug.Village{},
+	}
+
+	var buf bytes.Buffer
+	if err := opendataug.WriteBinary(&buf, ds); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := decode(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got, ds) {
+		t.Errorf("Expected %+v, got %+v", ds, got)
+	}
+
+	if _, err := decode([]byte("not binary")); err == nil {
+		t.Error("Expected an error, got nil")
+	}
+}
+
+func TestClient(t *testing.T) {
+	c, err := Client()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	again := MustClient()
+	if again != c {
+		t.Error("Expected the snapshot to be decoded once and shared")
+	}
+
+	var _ opendataug.API = c
+	if _, err := c.GetDistrictsContext(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
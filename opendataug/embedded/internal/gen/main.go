@@ -0,0 +1,61 @@
+// Command gen downloads the administrative hierarchy from the API and writes
+// it in the binary format as the snapshot embedded by package embedded. The
+// API key is read from the OPENDATAUG_API_KEY environment variable.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Open-Data-Uganda/opendataug-go/opendataug"
+)
+
+func main() {
+	out := flag.String("o", "dataset.bin", "output file")
+	timeout := flag.Duration("timeout", 10*time.Minute, "time allowed for the download")
+	flag.Parse()
+
+	apiKey := os.Getenv("OPENDATAUG_API_KEY")
+	if apiKey == "" {
+		log.Fatal("OPENDATAUG_API_KEY is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	ds, err := opendataug.LoadDataset(ctx, opendataug.NewClient(apiKey))
+	if err != nil {
+		log.Fatalf("loading dataset: %v", err)
+	}
+
+	if err := write(*out, ds); err != nil {
+		log.Fatalf("writing %s: %v", *out, err)
+	}
+
+	log.Printf("wrote %s: %d districts, %d counties, %d subcounties, %d parishes, %d villages",
+		*out, len(ds.Districts), len(ds.Counties), len(ds.Subcounties), len(ds.Parishes), len(ds.Villages))
+}
+
+// write encodes ds into a temporary file and renames it over name, so a
+// failed run leaves the previous snapshot in place
+func write(name string, ds *opendataug.Dataset) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := opendataug.WriteBinary(tmp, ds); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
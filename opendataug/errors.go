@@ -0,0 +1,48 @@
+package opendataug
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when the API responds with a non-200 status code or
+// an error message in the response body
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API request failed with status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API request failed with status code: %d: %s", e.StatusCode, e.Message)
+}
+
+// isNotFound reports whether err is an APIError for a missing resource or
+// an endpoint the server does not provide
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+var (
+	// ErrNotFound is returned when a lookup matches no unit
+	ErrNotFound = errors.New("opendataug: not found")
+
+	// ErrAmbiguousCode is returned when a code lookup matches more than one
+	// unit. Codes are only guaranteed to be unique within a parent, so the
+	// lookup should be repeated with the parent's ID.
+	ErrAmbiguousCode = errors.New("opendataug: code matches more than one unit")
+)
+
+// ErrAmbiguousName is returned when a name lookup matches more than one unit
+var ErrAmbiguousName = errors.New("opendataug: name matches more than one unit")
@@ -0,0 +1,98 @@
+package opendataug
+
+import (
+	"context"
+	"sync"
+)
+
+// fanOut calls fetch for every parent with at most limit calls in flight and
+// returns the results concatenated in parent order. The first failing call
+// cancels the remaining ones and its error is returned.
+func fanOut[P, T any](ctx context.Context, limit int, parents []P, fetch func(context.Context, P) ([]T, error)) ([]T, error) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	results := make([][]T, len(parents))
+	sem := make(chan struct{}, limit)
+
+dispatch:
+	for i, parent := range parents {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			items, err := fetch(ctx, parent)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = items
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, items := range results {
+		total += len(items)
+	}
+	all := make([]T, 0, total)
+	for _, items := range results {
+		all = append(all, items...)
+	}
+
+	return all, nil
+}
+
+// runAll calls every fn concurrently and waits for them to return. The first
+// failing call cancels the others and its error is returned.
+func runAll(ctx context.Context, fns ...func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, fn := range fns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,66 @@
+package opendataug
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFanOut(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	parents := []int{1, 2, 3, 4, 5, 6}
+	result, err := fanOut(context.Background(), 2, parents, func(ctx context.Context, n int) ([]int, error) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return []int{n, n * 10}, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []int{1, 10, 2, 20, 3, 30, 4, 40, 5, 50, 6, 60}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 calls in flight, got %d", maxInFlight)
+	}
+}
+
+func TestFanOutError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	_, err := fanOut(context.Background(), 2, []int{1, 2, 3}, func(ctx context.Context, n int) ([]int, error) {
+		if n == 2 {
+			return nil, errBoom
+		}
+		return []int{n}, nil
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Expected %v, got %v", errBoom, err)
+	}
+}
+
+func TestFanOutEmpty(t *testing.T) {
+	result, err := fanOut(context.Background(), 2, nil, func(ctx context.Context, n int) ([]int, error) {
+		return []int{n}, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result == nil || len(result) != 0 {
+		t.Errorf("Expected an empty slice, got %v", result)
+	}
+}
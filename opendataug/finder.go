@@ -0,0 +1,106 @@
+package opendataug
+
+import "fmt"
+
+// Finder looks units up by name in an AdminTree. Names are compared by their
+// DefaultNormalizer keys, and names that match no current unit are resolved
+// through an AliasRegistry, so historical names and alternative spellings
+// find the current record. A Finder is safe for concurrent use.
+type Finder struct {
+	tree    *AdminTree
+	aliases *AliasRegistry
+	names   map[levelName][]AdminUnit
+}
+
+// NewFinder indexes the names of every unit in tree. aliases may be nil.
+func NewFinder(tree *AdminTree, aliases *AliasRegistry) *Finder {
+	f := &Finder{
+		tree:    tree,
+		aliases: aliases,
+		names:   make(map[levelName][]AdminUnit),
+	}
+
+	for u := range tree.DepthFirst(nil) {
+		key := levelName{level: u.Level(), key: DefaultNormalizer.Key(u.UnitName())}
+		f.names[key] = append(f.names[key], u)
+	}
+
+	return f
+}
+
+// Find returns every unit at level named name, either currently or through
+// an alias. Current names take precedence over aliases.
+func (f *Finder) Find(level Level, name string) []AdminUnit {
+	key := levelName{level: level, key: DefaultNormalizer.Key(name)}
+	if found := f.names[key]; len(found) > 0 {
+		return append([]AdminUnit(nil), found...)
+	}
+
+	if f.aliases == nil {
+		return nil
+	}
+
+	var found []AdminUnit
+	for _, alias := range f.aliases.Resolve(level, name) {
+		if u, ok := f.tree.Unit(level, alias.ID); ok {
+			found = append(found, u)
+		}
+	}
+	return found
+}
+
+// FindDistrict returns the district named name
+func (f *Finder) FindDistrict(name string) (District, error) {
+	u, err := f.findOne(LevelDistrict, name)
+	if err != nil {
+		return District{}, err
+	}
+	return u.(District), nil
+}
+
+// FindCounty returns the county named name
+func (f *Finder) FindCounty(name string) (County, error) {
+	u, err := f.findOne(LevelCounty, name)
+	if err != nil {
+		return County{}, err
+	}
+	return u.(County), nil
+}
+
+// FindSubcounty returns the subcounty named name
+func (f *Finder) FindSubcounty(name string) (Subcounty, error) {
+	u, err := f.findOne(LevelSubcounty, name)
+	if err != nil {
+		return Subcounty{}, err
+	}
+	return u.(Subcounty), nil
+}
+
+// FindParish returns the parish named name
+func (f *Finder) FindParish(name string) (Parish, error) {
+	u, err := f.findOne(LevelParish, name)
+	if err != nil {
+		return Parish{}, err
+	}
+	return u.(Parish), nil
+}
+
+// FindVillage returns the village named name
+func (f *Finder) FindVillage(name string) (Village, error) {
+	u, err := f.findOne(LevelVillage, name)
+	if err != nil {
+		return Village{}, err
+	}
+	return u.(Village), nil
+}
+
+func (f *Finder) findOne(level Level, name string) (AdminUnit, error) {
+	found := f.Find(level, name)
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s named %q", ErrNotFound, level, name)
+	case 1:
+		return found[0], nil
+	}
+	return nil, fmt.Errorf("%w: %s named %q", ErrAmbiguousName, level, name)
+}
@@ -0,0 +1,71 @@
+package opendataug
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFinderFindDistrict(t *testing.T) {
+	ds := testDataset()
+	ds.Districts = append(ds.Districts, District{ID: "district-5", Name: "Ssembabule", RegionID: "region-1", RegionName: "Central"})
+
+	aliases := NewAliasRegistry(
+		Alias{Name: "Sembabule", Level: LevelDistrict, ID: "district-5", Kind: "spelling"},
+		Alias{Name: "Kampala Capital City", Level: LevelDistrict, ID: "district-1", Kind: "historical"},
+		Alias{Name: "Wakiso", Level: LevelDistrict, ID: "district-missing"},
+	)
+	finder := NewFinder(NewAdminTree(ds), aliases)
+
+	tests := []struct {
+		name          string
+		input         string
+		expectedID    DistrictID
+		expectedError error
+	}{
+		{name: "Current name", input: "Ssembabule", expectedID: "district-5"},
+		{name: "Current name with qualifier", input: "wakiso district", expectedID: "district-2"},
+		{name: "Alias", input: "Sembabule", expectedID: "district-5"},
+		{name: "Historical name", input: "KAMPALA CAPITAL CITY", expectedID: "district-1"},
+		{name: "Unknown", input: "Atlantis", expectedError: ErrNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			district, err := finder.FindDistrict(tc.input)
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if district.ID != tc.expectedID {
+				t.Errorf("Expected %q, got %q", tc.expectedID, district.ID)
+			}
+		})
+	}
+}
+
+func TestFinderAmbiguous(t *testing.T) {
+	ds := testDataset()
+	ds.Parishes = append(ds.Parishes, Parish{ID: "parish-5", Name: "Kiwatule", SubcountyID: "subcounty-3"})
+
+	finder := NewFinder(NewAdminTree(ds), nil)
+
+	if _, err := finder.FindParish("Kiwatule"); !errors.Is(err, ErrAmbiguousName) {
+		t.Errorf("Expected %v, got %v", ErrAmbiguousName, err)
+	}
+
+	if units := finder.Find(LevelParish, "kiwatule ward"); len(units) != 2 {
+		t.Errorf("Expected 2 parishes, got %d", len(units))
+	}
+
+	village, err := finder.FindVillage("Kazo Central")
+	if err != nil || village.ID != "village-4" {
+		t.Errorf("Expected village-4, got %+v, %v", village, err)
+	}
+
+	if _, err := finder.FindCounty("Nakawa"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, err := finder.FindSubcounty("Nangabo S/C"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package opendataug
+
+import (
+	"sort"
+	"strings"
+)
+
+// Levenshtein returns the number of single-character insertions, deletions
+// and substitutions needed to turn a into b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// LevenshteinSimilarity scales the Levenshtein distance between a and b to a
+// similarity from 0 to 1, where 1 means the strings are equal
+func LevenshteinSimilarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b from 0 to 1,
+// which favours strings sharing a prefix
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo := max(0, i-window)
+		hi := min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if matchedB[j] || ra[i] != rb[j] {
+				continue
+			}
+			matchedA[i], matchedB[j] = true, true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// PhoneticKey reduces a place name to a key that is equal for common
+// spelling variants of Ugandan names. On top of DefaultNormalizer.Key it
+// treats l and r as the same letter, writes "ky" before a vowel as "ki", and
+// collapses doubled letters, so "Kaseese", "Kasese", "Kyotera T.C." and
+// "Kiotera" pair up.
+func PhoneticKey(name string) string {
+	words := strings.Fields(DefaultNormalizer.Key(name))
+	for i, word := range words {
+		words[i] = phoneticWord(word)
+	}
+	return strings.Join(words, " ")
+}
+
+func phoneticWord(word string) string {
+	runes := []rune(word)
+	out := make([]rune, 0, len(runes))
+
+	for i, r := range runes {
+		if r == 'r' {
+			r = 'l'
+		}
+		if r == 'y' && i > 0 && runes[i-1] == 'k' && i+1 < len(runes) && isVowel(runes[i+1]) {
+			r = 'i'
+		}
+		if len(out) > 0 && out[len(out)-1] == r {
+			continue
+		}
+		out = append(out, r)
+	}
+
+	return string(out)
+}
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiou", r)
+}
+
+// NameSimilarity scores how likely a and b are to name the same place, from 0
+// to 1. Names that are equal once qualifiers are stripped score 1, names with
+// equal phonetic keys at least 0.95, and other names the higher of their
+// Jaro-Winkler and Levenshtein similarities.
+func NameSimilarity(a, b string) float64 {
+	ka, kb := DefaultNormalizer.Key(a), DefaultNormalizer.Key(b)
+	if ka == kb {
+		return 1
+	}
+
+	score := max(JaroWinkler(ka, kb), LevenshteinSimilarity(ka, kb))
+	if PhoneticKey(a) == PhoneticKey(b) {
+		score = max(score, 0.95)
+	}
+	return score
+}
+
+// NameMatch is a unit whose name matched a query
+type NameMatch struct {
+	Unit  AdminUnit `json:"unit"`
+	Score float64   `json:"score"`
+}
+
+// DefaultMinScore is the lowest score a Matcher returns unless configured
+// otherwise
+const DefaultMinScore = 0.85
+
+// Matcher finds units by approximate name. It is safe for concurrent use as
+// long as MinScore is not changed.
+type Matcher struct {
+	// MinScore is the lowest score of a returned match
+	MinScore float64
+
+	tree *AdminTree
+}
+
+// NewMatcher returns a Matcher over the units linked into tree
+func NewMatcher(tree *AdminTree) *Matcher {
+	return &Matcher{MinScore: DefaultMinScore, tree: tree}
+}
+
+// Match returns the units whose names are similar to name, best first. Only
+// units at level are considered unless it is LevelUnknown, and only children
+// of the unit with parentID one level up unless parentID is empty.
+func (m *Matcher) Match(name string, level Level, parentID string) []NameMatch {
+	var candidates []AdminUnit
+	switch {
+	case parentID != "" && level.Valid() && level != LevelDistrict:
+		if parent, ok := m.tree.Unit(level.Parent(), parentID); ok {
+			candidates = m.tree.Children(parent)
+		}
+	default:
+		for u := range m.tree.DepthFirst(nil) {
+			if level.Valid() && u.Level() != level {
+				continue
+			}
+			if parentID != "" && u.ParentID() != parentID {
+				continue
+			}
+			candidates = append(candidates, u)
+		}
+	}
+
+	var matches []NameMatch
+	for _, u := range candidates {
+		score := NameSimilarity(name, u.UnitName())
+		if score >= m.MinScore {
+			matches = append(matches, NameMatch{Unit: u, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return matches
+}
@@ -0,0 +1,131 @@
+package opendataug
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "kasese", b: "", expected: 6},
+		{a: "kasese", b: "kaseese", expected: 1},
+		{a: "kitten", b: "sitting", expected: 3},
+		{a: "mbarara", b: "mbalala", expected: 2},
+		{a: "gulu", b: "gulu", expected: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			if d := Levenshtein(tc.a, tc.b); d != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, d)
+			}
+			if d := Levenshtein(tc.b, tc.a); d != tc.expected {
+				t.Errorf("Expected %d for swapped arguments, got %d", tc.expected, d)
+			}
+		})
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{a: "martha", b: "marhta", expected: 0.9611},
+		{a: "dixon", b: "dicksonx", expected: 0.8133},
+		{a: "kasese", b: "kasese", expected: 1},
+		{a: "abc", b: "xyz", expected: 0},
+		{a: "", b: "", expected: 1},
+		{a: "gulu", b: "", expected: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			if score := JaroWinkler(tc.a, tc.b); math.Abs(score-tc.expected) > 0.0001 {
+				t.Errorf("Expected %.4f, got %.4f", tc.expected, score)
+			}
+		})
+	}
+}
+
+func TestPhoneticKey(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{a: "Kasese", b: "Kaseese", equal: true},
+		{a: "Kyotera", b: "Kyotera T.C.", equal: true},
+		{a: "Kyotera", b: "Kiotera", equal: true},
+		{a: "Mbarara", b: "Mbalala", equal: true},
+		{a: "Ssembabule", b: "Sembabule", equal: true},
+		{a: "Kyenjojo", b: "Kienjojo", equal: true},
+		{a: "Gulu", b: "Lira", equal: false},
+		{a: "Kasese", b: "Kisoro", equal: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			ka, kb := PhoneticKey(tc.a), PhoneticKey(tc.b)
+			if (ka == kb) != tc.equal {
+				t.Errorf("Expected equal=%v, got keys %q and %q", tc.equal, ka, kb)
+			}
+		})
+	}
+}
+
+func TestMatcherMatch(t *testing.T) {
+	ds := testDataset()
+	ds.Districts = append(ds.Districts,
+		District{ID: "district-3", Name: "Kasese", RegionID: "region-2", RegionName: "Western"},
+		District{ID: "district-4", Name: "Kisoro", RegionID: "region-2", RegionName: "Western"},
+	)
+	matcher := NewMatcher(NewAdminTree(ds))
+
+	matches := matcher.Match("Kaseese", LevelDistrict, "")
+	if len(matches) != 1 || matches[0].Unit.UnitID() != "district-3" {
+		t.Fatalf("Expected only district-3, got %+v", matches)
+	}
+	if matches[0].Score < 0.95 {
+		t.Errorf("Expected a phonetic match to score at least 0.95, got %v", matches[0].Score)
+	}
+
+	matches = matcher.Match("Kiwatule", LevelParish, "subcounty-1")
+	if len(matches) != 1 || matches[0].Unit.UnitID() != "parish-1" || matches[0].Score != 1 {
+		t.Errorf("Expected an exact match on parish-1, got %+v", matches)
+	}
+
+	if matches := matcher.Match("Kiwatule", LevelParish, "subcounty-2"); len(matches) != 0 {
+		t.Errorf("Expected no matches under subcounty-2, got %+v", matches)
+	}
+
+	matches = matcher.Match("Kawempe", LevelUnknown, "")
+	if len(matches) != 2 {
+		t.Fatalf("Expected the county and subcounty, got %+v", matches)
+	}
+
+	matches = matcher.Match("Kiwatul", LevelVillage, "")
+	for i := 1; i < len(matches); i++ {
+		if matches[i].Score > matches[i-1].Score {
+			t.Errorf("Expected matches to be ranked by score, got %+v", matches)
+		}
+	}
+}
+
+func TestAddressParserPhoneticMatch(t *testing.T) {
+	parser := NewAddressParser(NewAdminTree(testDataset()))
+
+	candidates := parser.Parse("Kawempe, Kampaala")
+	if len(candidates) == 0 {
+		t.Fatal("Expected candidates, got none")
+	}
+	if candidates[0].Path.District.ID != "district-1" {
+		t.Errorf("Expected a path in district-1, got %+v", candidates[0].Path)
+	}
+	if candidates[0].Score >= 1 || candidates[0].Score < 0.9 {
+		t.Errorf("Expected a score between 0.9 and 1, got %v", candidates[0].Score)
+	}
+}
@@ -0,0 +1,134 @@
+package opendataug
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// DistrictID identifies a District
+type DistrictID string
+
+// CountyID identifies a County
+type CountyID string
+
+// SubcountyID identifies a Subcounty
+type SubcountyID string
+
+// ParishID identifies a Parish
+type ParishID string
+
+// VillageID identifies a Village
+type VillageID string
+
+func (id DistrictID) String() string  { return string(id) }
+func (id CountyID) String() string    { return string(id) }
+func (id SubcountyID) String() string { return string(id) }
+func (id ParishID) String() string    { return string(id) }
+func (id VillageID) String() string   { return string(id) }
+
+// MarshalText implements encoding.TextMarshaler
+func (id DistrictID) MarshalText() ([]byte, error) { return []byte(id), nil }
+
+// MarshalText implements encoding.TextMarshaler
+func (id CountyID) MarshalText() ([]byte, error) { return []byte(id), nil }
+
+// MarshalText implements encoding.TextMarshaler
+func (id SubcountyID) MarshalText() ([]byte, error) { return []byte(id), nil }
+
+// MarshalText implements encoding.TextMarshaler
+func (id ParishID) MarshalText() ([]byte, error) { return []byte(id), nil }
+
+// MarshalText implements encoding.TextMarshaler
+func (id VillageID) MarshalText() ([]byte, error) { return []byte(id), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *DistrictID) UnmarshalText(data []byte) error {
+	*id = DistrictID(data)
+	return nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *CountyID) UnmarshalText(data []byte) error {
+	*id = CountyID(data)
+	return nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *SubcountyID) UnmarshalText(data []byte) error {
+	*id = SubcountyID(data)
+	return nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *ParishID) UnmarshalText(data []byte) error {
+	*id = ParishID(data)
+	return nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *VillageID) UnmarshalText(data []byte) error {
+	*id = VillageID(data)
+	return nil
+}
+
+// Value implements driver.Valuer
+func (id DistrictID) Value() (driver.Value, error) { return string(id), nil }
+
+// Value implements driver.Valuer
+func (id CountyID) Value() (driver.Value, error) { return string(id), nil }
+
+// Value implements driver.Valuer
+func (id SubcountyID) Value() (driver.Value, error) { return string(id), nil }
+
+// Value implements driver.Valuer
+func (id ParishID) Value() (driver.Value, error) { return string(id), nil }
+
+// Value implements driver.Valuer
+func (id VillageID) Value() (driver.Value, error) { return string(id), nil }
+
+// Scan implements sql.Scanner
+func (id *DistrictID) Scan(src interface{}) error {
+	s, err := scanID(src, "DistrictID")
+	*id = DistrictID(s)
+	return err
+}
+
+// Scan implements sql.Scanner
+func (id *CountyID) Scan(src interface{}) error {
+	s, err := scanID(src, "CountyID")
+	*id = CountyID(s)
+	return err
+}
+
+// Scan implements sql.Scanner
+func (id *SubcountyID) Scan(src interface{}) error {
+	s, err := scanID(src, "SubcountyID")
+	*id = SubcountyID(s)
+	return err
+}
+
+// Scan implements sql.Scanner
+func (id *ParishID) Scan(src interface{}) error {
+	s, err := scanID(src, "ParishID")
+	*id = ParishID(s)
+	return err
+}
+
+// Scan implements sql.Scanner
+func (id *VillageID) Scan(src interface{}) error {
+	s, err := scanID(src, "VillageID")
+	*id = VillageID(s)
+	return err
+}
+
+func scanID(src interface{}, kind string) (string, error) {
+	switch v := src.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+	return "", fmt.Errorf("opendataug: cannot scan %T into %s", src, kind)
+}
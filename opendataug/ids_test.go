@@ -0,0 +1,72 @@
+package opendataug
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestIDJSON(t *testing.T) {
+	input := `{"district-1":["county-1","county-2"]}`
+
+	var counties map[DistrictID][]CountyID
+	if err := json.Unmarshal([]byte(input), &counties); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[DistrictID][]CountyID{
+		"district-1": {"county-1", "county-2"},
+	}
+	if !reflect.DeepEqual(counties, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, counties)
+	}
+
+	output, err := json.Marshal(counties)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(output) != input {
+		t.Errorf("Expected %s, got %s", input, output)
+	}
+}
+
+func TestIDSQL(t *testing.T) {
+	tests := []struct {
+		name        string
+		src         interface{}
+		expected    ParishID
+		expectError bool
+	}{
+		{name: "String", src: "parish-1", expected: "parish-1"},
+		{name: "Bytes", src: []byte("parish-2"), expected: "parish-2"},
+		{name: "Null", src: nil, expected: ""},
+		{name: "Unsupported", src: 42, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var id ParishID
+			err := id.Scan(tc.src)
+
+			if tc.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if id != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, id)
+			}
+		})
+	}
+
+	value, err := VillageID("village-1").Value()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if value != "village-1" {
+		t.Errorf("Expected village-1, got %v", value)
+	}
+}
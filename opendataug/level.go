@@ -0,0 +1,107 @@
+package opendataug
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is a level of the administrative hierarchy. Levels are ordered from
+// the top of the hierarchy down, so LevelDistrict < LevelVillage.
+type Level int
+
+const (
+	LevelUnknown Level = iota
+	LevelDistrict
+	LevelCounty
+	LevelSubcounty
+	LevelParish
+	LevelVillage
+)
+
+// Levels lists every valid level from the top of the hierarchy down
+var Levels = []Level{LevelDistrict, LevelCounty, LevelSubcounty, LevelParish, LevelVillage}
+
+var levelNames = map[Level]string{
+	LevelDistrict:  "district",
+	LevelCounty:    "county",
+	LevelSubcounty: "subcounty",
+	LevelParish:    "parish",
+	LevelVillage:   "village",
+}
+
+var levelAliases = map[string]Level{
+	"district":    LevelDistrict,
+	"districts":   LevelDistrict,
+	"county":      LevelCounty,
+	"counties":    LevelCounty,
+	"subcounty":   LevelSubcounty,
+	"subcounties": LevelSubcounty,
+	"parish":      LevelParish,
+	"parishes":    LevelParish,
+	"village":     LevelVillage,
+	"villages":    LevelVillage,
+}
+
+// ParseLevel parses the name of a level. It is case-insensitive and accepts
+// plurals and the "sub-county" and "sub county" spellings.
+func ParseLevel(s string) (Level, error) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	key = strings.NewReplacer("-", "", " ", "", "_", "").Replace(key)
+
+	if level, ok := levelAliases[key]; ok {
+		return level, nil
+	}
+	return LevelUnknown, fmt.Errorf("opendataug: unknown level %q", s)
+}
+
+// Valid reports whether l is one of the five levels of the hierarchy
+func (l Level) Valid() bool {
+	return l >= LevelDistrict && l <= LevelVillage
+}
+
+// String returns the lower-case name of the level
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// Parent returns the level above l, or LevelUnknown for districts
+func (l Level) Parent() Level {
+	if l <= LevelDistrict || l > LevelVillage {
+		return LevelUnknown
+	}
+	return l - 1
+}
+
+// Child returns the level below l, or LevelUnknown for villages
+func (l Level) Child() Level {
+	if l < LevelDistrict || l >= LevelVillage {
+		return LevelUnknown
+	}
+	return l + 1
+}
+
+// Above reports whether l is higher in the hierarchy than other
+func (l Level) Above(other Level) bool {
+	return l.Valid() && other.Valid() && l < other
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (l Level) MarshalText() ([]byte, error) {
+	if !l.Valid() {
+		return nil, fmt.Errorf("opendataug: cannot marshal invalid level %d", int(l))
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (l *Level) UnmarshalText(data []byte) error {
+	level, err := ParseLevel(string(data))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
@@ -0,0 +1,102 @@
+package opendataug
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    Level
+		expectError bool
+	}{
+		{input: "district", expected: LevelDistrict},
+		{input: "Counties", expected: LevelCounty},
+		{input: "sub-county", expected: LevelSubcounty},
+		{input: "Sub County", expected: LevelSubcounty},
+		{input: "subcounties", expected: LevelSubcounty},
+		{input: " PARISH ", expected: LevelParish},
+		{input: "villages", expected: LevelVillage},
+		{input: "region", expected: LevelUnknown, expectError: true},
+		{input: "", expected: LevelUnknown, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			level, err := ParseLevel(tc.input)
+
+			if tc.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if level != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, level)
+			}
+		})
+	}
+}
+
+func TestLevelNavigation(t *testing.T) {
+	tests := []struct {
+		level          Level
+		expectedParent Level
+		expectedChild  Level
+	}{
+		{level: LevelDistrict, expectedParent: LevelUnknown, expectedChild: LevelCounty},
+		{level: LevelCounty, expectedParent: LevelDistrict, expectedChild: LevelSubcounty},
+		{level: LevelSubcounty, expectedParent: LevelCounty, expectedChild: LevelParish},
+		{level: LevelParish, expectedParent: LevelSubcounty, expectedChild: LevelVillage},
+		{level: LevelVillage, expectedParent: LevelParish, expectedChild: LevelUnknown},
+		{level: LevelUnknown, expectedParent: LevelUnknown, expectedChild: LevelUnknown},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			if tc.level.Parent() != tc.expectedParent {
+				t.Errorf("Expected parent %v, got %v", tc.expectedParent, tc.level.Parent())
+			}
+			if tc.level.Child() != tc.expectedChild {
+				t.Errorf("Expected child %v, got %v", tc.expectedChild, tc.level.Child())
+			}
+		})
+	}
+
+	if !LevelDistrict.Above(LevelParish) {
+		t.Error("Expected district to be above parish")
+	}
+	if LevelVillage.Above(LevelParish) {
+		t.Error("Expected village not to be above parish")
+	}
+	if LevelUnknown.Above(LevelVillage) {
+		t.Error("Expected unknown level not to be above village")
+	}
+}
+
+func TestLevelJSON(t *testing.T) {
+	output, err := json.Marshal(map[string]Level{"level": LevelSubcounty})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(output) != `{"level":"subcounty"}` {
+		t.Errorf("Expected {\"level\":\"subcounty\"}, got %s", output)
+	}
+
+	var decoded struct {
+		Level Level `json:"level"`
+	}
+	if err := json.Unmarshal([]byte(`{"level":"Parishes"}`), &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded.Level != LevelParish {
+		t.Errorf("Expected parish, got %v", decoded.Level)
+	}
+
+	if _, err := json.Marshal(LevelUnknown); err == nil {
+		t.Error("Expected an error marshaling an unknown level, got nil")
+	}
+}
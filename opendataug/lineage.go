@@ -0,0 +1,247 @@
+package opendataug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// LineageDistrict is a district as recorded in a lineage file, including
+// districts that no longer exist
+type LineageDistrict struct {
+	ID   DistrictID `json:"id"`
+	Name string     `json:"name"`
+	// Created is when the district came into existence. The zero value
+	// means before the start of the records.
+	Created Timestamp `json:"created,omitzero"`
+	// Dissolved is when the district ceased to exist. The zero value means
+	// it still exists.
+	Dissolved Timestamp `json:"dissolved,omitzero"`
+}
+
+// ExistsAt reports whether the district existed at t
+func (d LineageDistrict) ExistsAt(t time.Time) bool {
+	if !d.Created.Time.IsZero() && t.Before(d.Created.Time) {
+		return false
+	}
+	if !d.Dissolved.Time.IsZero() && !t.Before(d.Dissolved.Time) {
+		return false
+	}
+	return true
+}
+
+// LineageLink records that territory of Parent became part of Child on
+// Effective. A district split off from another has a link from the old
+// district; a merged district has a link from each of the districts merged.
+type LineageLink struct {
+	Parent    DistrictID `json:"parent"`
+	Child     DistrictID `json:"child"`
+	Effective Timestamp  `json:"effective"`
+}
+
+// LineageData is the content of a lineage file
+type LineageData struct {
+	Version   string            `json:"version,omitempty"`
+	Districts []LineageDistrict `json:"districts"`
+	Links     []LineageLink     `json:"links"`
+}
+
+// Lineage records how districts were split and merged over time so that
+// figures reported against the districts of one date can be mapped onto the
+// districts of another. A Lineage is immutable and safe for concurrent use.
+type Lineage struct {
+	version   string
+	districts map[DistrictID]LineageDistrict
+	children  map[DistrictID][]LineageLink
+	parents   map[DistrictID][]LineageLink
+}
+
+// NewLineage validates data and builds a Lineage from it
+func NewLineage(data LineageData) (*Lineage, error) {
+	l := &Lineage{
+		version:   data.Version,
+		districts: make(map[DistrictID]LineageDistrict, len(data.Districts)),
+		children:  make(map[DistrictID][]LineageLink),
+		parents:   make(map[DistrictID][]LineageLink),
+	}
+
+	for _, d := range data.Districts {
+		if d.ID == "" {
+			return nil, fmt.Errorf("lineage: district %q has no id", d.Name)
+		}
+		if _, ok := l.districts[d.ID]; ok {
+			return nil, fmt.Errorf("lineage: duplicate district %s", d.ID)
+		}
+		if err := checkLineageDate(d.Created); err != nil {
+			return nil, fmt.Errorf("lineage: district %s has an invalid created date: %w", d.ID, err)
+		}
+		if err := checkLineageDate(d.Dissolved); err != nil {
+			return nil, fmt.Errorf("lineage: district %s has an invalid dissolved date: %w", d.ID, err)
+		}
+		if !d.Created.Time.IsZero() && !d.Dissolved.Time.IsZero() && !d.Created.Before(d.Dissolved.Time) {
+			return nil, fmt.Errorf("lineage: district %s is dissolved before it is created", d.ID)
+		}
+		l.districts[d.ID] = d
+	}
+
+	for _, link := range data.Links {
+		if _, ok := l.districts[link.Parent]; !ok {
+			return nil, fmt.Errorf("lineage: link from unknown district %s", link.Parent)
+		}
+		if _, ok := l.districts[link.Child]; !ok {
+			return nil, fmt.Errorf("lineage: link to unknown district %s", link.Child)
+		}
+		if link.Parent == link.Child {
+			return nil, fmt.Errorf("lineage: district %s is linked to itself", link.Parent)
+		}
+		if err := checkLineageDate(link.Effective); err != nil {
+			return nil, fmt.Errorf("lineage: link from %s to %s has an invalid effective date: %w", link.Parent, link.Child, err)
+		}
+		if link.Effective.Time.IsZero() {
+			return nil, fmt.Errorf("lineage: link from %s to %s has no effective date", link.Parent, link.Child)
+		}
+		l.children[link.Parent] = append(l.children[link.Parent], link)
+		l.parents[link.Child] = append(l.parents[link.Child], link)
+	}
+
+	return l, nil
+}
+
+// checkLineageDate rejects a date whose text could not be parsed. Decoding
+// keeps such text rather than failing, but in a lineage a zero time means
+// the date is open-ended, so a typo must not be read as one.
+func checkLineageDate(t Timestamp) error {
+	if t.Time.IsZero() && t.Raw() != "" {
+		return fmt.Errorf("cannot parse %q", t.Raw())
+	}
+	return nil
+}
+
+// LoadLineage reads a lineage from a JSON file
+func LoadLineage(path string) (*Lineage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadLineage(f)
+}
+
+// ReadLineage reads a lineage from JSON
+func ReadLineage(r io.Reader) (*Lineage, error) {
+	var data LineageData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("lineage: %w", err)
+	}
+	return NewLineage(data)
+}
+
+// Version returns the version of the lineage data
+func (l *Lineage) Version() string {
+	return l.version
+}
+
+// District returns the district with the given ID
+func (l *Lineage) District(id DistrictID) (LineageDistrict, bool) {
+	d, ok := l.districts[id]
+	return d, ok
+}
+
+// DistrictsAt returns the IDs of the districts that existed at t, sorted
+func (l *Lineage) DistrictsAt(t time.Time) []DistrictID {
+	var ids []DistrictID
+	for id, d := range l.districts {
+		if d.ExistsAt(t) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Map returns the districts that existed at to and cover territory of the
+// district id as it was at from, sorted. It follows links forwards when to
+// is after from and backwards when it is before.
+func (l *Lineage) Map(id DistrictID, from, to time.Time) ([]DistrictID, error) {
+	d, ok := l.districts[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: district %s in lineage", ErrNotFound, id)
+	}
+	if !d.ExistsAt(from) {
+		return nil, fmt.Errorf("lineage: district %s did not exist at %s", id, from.Format(time.DateOnly))
+	}
+
+	if to.Before(from) {
+		return l.Predecessors(id, from, to), nil
+	}
+	return l.Successors(id, from, to), nil
+}
+
+// Successors returns the districts that existed at to and took over territory
+// of the district id as it was at from, sorted. A district that still exists
+// at to is its own successor.
+func (l *Lineage) Successors(id DistrictID, from, to time.Time) []DistrictID {
+	found := make(map[DistrictID]bool)
+	visited := make(map[DistrictID]bool)
+
+	var walk func(id DistrictID, since time.Time)
+	walk = func(id DistrictID, since time.Time) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+
+		if l.districts[id].ExistsAt(to) {
+			found[id] = true
+		}
+		for _, link := range l.children[id] {
+			if link.Effective.After(since) && !link.Effective.After(to) {
+				walk(link.Child, link.Effective.Time)
+			}
+		}
+	}
+	walk(id, from)
+
+	return sortedDistrictIDs(found)
+}
+
+// Predecessors returns the districts that existed at to and held territory
+// of the district id as it was at from, sorted. A district that already
+// existed at to is its own predecessor.
+func (l *Lineage) Predecessors(id DistrictID, from, to time.Time) []DistrictID {
+	found := make(map[DistrictID]bool)
+	visited := make(map[DistrictID]bool)
+
+	var walk func(id DistrictID, until time.Time)
+	walk = func(id DistrictID, until time.Time) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+
+		if l.districts[id].ExistsAt(to) {
+			found[id] = true
+		}
+		for _, link := range l.parents[id] {
+			if link.Effective.After(to) && !link.Effective.After(until) {
+				walk(link.Parent, link.Effective.Time)
+			}
+		}
+	}
+	walk(id, from)
+
+	return sortedDistrictIDs(found)
+}
+
+func sortedDistrictIDs(set map[DistrictID]bool) []DistrictID {
+	ids := make([]DistrictID, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
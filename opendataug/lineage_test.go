@@ -0,0 +1,129 @@
+package opendataug
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testLineage = `{
+	"version": "2024-07",
+	"districts": [
+		{"id": "mubende", "name": "Mubende"},
+		{"id": "mityana", "name": "Mityana", "created": "2005-07-01"},
+		{"id": "kassanda", "name": "Kassanda", "created": "2019-07-01"},
+		{"id": "old-north", "name": "Old North", "dissolved": "2015-01-01"},
+		{"id": "old-south", "name": "Old South", "dissolved": "2015-01-01"},
+		{"id": "united", "name": "United", "created": "2015-01-01"}
+	],
+	"links": [
+		{"parent": "mubende", "child": "mityana", "effective": "2005-07-01"},
+		{"parent": "mubende", "child": "kassanda", "effective": "2019-07-01"},
+		{"parent": "old-north", "child": "united", "effective": "2015-01-01"},
+		{"parent": "old-south", "child": "united", "effective": "2015-01-01"}
+	]
+}`
+
+func year(y int) time.Time {
+	return time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestLineageMap(t *testing.T) {
+	lineage, err := ReadLineage(strings.NewReader(testLineage))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		id       DistrictID
+		from, to time.Time
+		expected []DistrictID
+	}{
+		{name: "Split forwards", id: "mubende", from: year(2000), to: year(2020), expected: []DistrictID{"kassanda", "mityana", "mubende"}},
+		{name: "Split forwards partially", id: "mubende", from: year(2000), to: year(2010), expected: []DistrictID{"mityana", "mubende"}},
+		{name: "Split after from", id: "mubende", from: year(2010), to: year(2020), expected: []DistrictID{"kassanda", "mubende"}},
+		{name: "Split backwards", id: "kassanda", from: year(2020), to: year(2000), expected: []DistrictID{"mubende"}},
+		{name: "Split backwards to parent", id: "mityana", from: year(2020), to: year(2010), expected: []DistrictID{"mityana"}},
+		{name: "Merge forwards", id: "old-north", from: year(2010), to: year(2020), expected: []DistrictID{"united"}},
+		{name: "Merge backwards", id: "united", from: year(2020), to: year(2010), expected: []DistrictID{"old-north", "old-south"}},
+		{name: "Same date", id: "united", from: year(2020), to: year(2020), expected: []DistrictID{"united"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ids, err := lineage.Map(tc.id, tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(ids, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, ids)
+			}
+		})
+	}
+}
+
+func TestLineageMapErrors(t *testing.T) {
+	lineage, err := ReadLineage(strings.NewReader(testLineage))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := lineage.Map("atlantis", year(2000), year(2020)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %v, got %v", ErrNotFound, err)
+	}
+
+	if _, err := lineage.Map("mityana", year(2000), year(2020)); err == nil {
+		t.Error("Expected an error for a district that did not exist yet, got nil")
+	}
+}
+
+func TestLineageDistrictsAt(t *testing.T) {
+	lineage, err := ReadLineage(strings.NewReader(testLineage))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []DistrictID{"mityana", "mubende", "old-north", "old-south"}
+	if ids := lineage.DistrictsAt(year(2010)); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+
+	expected = []DistrictID{"kassanda", "mityana", "mubende", "united"}
+	if ids := lineage.DistrictsAt(year(2020)); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+
+	if lineage.Version() != "2024-07" {
+		t.Errorf("Expected version 2024-07, got %s", lineage.Version())
+	}
+}
+
+func TestReadLineageErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Invalid JSON", input: `{"districts": [}`},
+		{name: "Missing ID", input: `{"districts": [{"name": "Mubende"}]}`},
+		{name: "Duplicate district", input: `{"districts": [{"id": "a"}, {"id": "a"}]}`},
+		{name: "Dissolved before created", input: `{"districts": [{"id": "a", "created": "2010-01-01", "dissolved": "2005-01-01"}]}`},
+		{name: "Unknown parent", input: `{"districts": [{"id": "a"}], "links": [{"parent": "b", "child": "a", "effective": "2010-01-01"}]}`},
+		{name: "Unknown child", input: `{"districts": [{"id": "a"}], "links": [{"parent": "a", "child": "b", "effective": "2010-01-01"}]}`},
+		{name: "Self link", input: `{"districts": [{"id": "a"}], "links": [{"parent": "a", "child": "a", "effective": "2010-01-01"}]}`},
+		{name: "Missing date", input: `{"districts": [{"id": "a"}, {"id": "b"}], "links": [{"parent": "a", "child": "b"}]}`},
+		{name: "Unparseable created", input: `{"districts": [{"id": "a", "created": "01/07/2010"}]}`},
+		{name: "Unparseable dissolved", input: `{"districts": [{"id": "a", "dissolved": "2010-13-45"}]}`},
+		{name: "Unparseable effective", input: `{"districts": [{"id": "a"}, {"id": "b"}], "links": [{"parent": "a", "child": "b", "effective": "July 2010"}]}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ReadLineage(strings.NewReader(tc.input)); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package opendataug
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// memo caches the results of fetch by key. Concurrent calls for the same key
+// share a single fetch, and failed fetches are not cached.
+type memo[K comparable, V any] struct {
+	mu      sync.Mutex
+	entries map[K]*memoEntry[V]
+}
+
+type memoEntry[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func (m *memo[K, V]) get(ctx context.Context, key K, fetch func(context.Context, K) (V, error)) (V, error) {
+	for {
+		m.mu.Lock()
+		if m.entries == nil {
+			m.entries = make(map[K]*memoEntry[V])
+		}
+
+		e, ok := m.entries[key]
+		if !ok {
+			break
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+
+		// A fetch cut short by its caller's context says nothing about the
+		// key, so a waiter whose own context is live fetches it again
+		if isContextErr(e.err) && ctx.Err() == nil {
+			continue
+		}
+		return e.value, e.err
+	}
+
+	e := &memoEntry[V]{done: make(chan struct{})}
+	m.entries[key] = e
+	m.mu.Unlock()
+
+	e.value, e.err = fetch(ctx, key)
+	if e.err != nil {
+		m.mu.Lock()
+		delete(m.entries, key)
+		m.mu.Unlock()
+	}
+	close(e.done)
+
+	return e.value, e.err
+}
+
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (m *memo[K, V]) reset() {
+	m.mu.Lock()
+	m.entries = nil
+	m.mu.Unlock()
+}
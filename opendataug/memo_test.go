@@ -0,0 +1,52 @@
+package opendataug
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoWaiterRetriesCancelledFetch(t *testing.T) {
+	var m memo[string, int]
+
+	started := make(chan struct{})
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan error, 1)
+
+	go func() {
+		_, err := m.get(firstCtx, "key", func(ctx context.Context, _ string) (int, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		firstDone <- err
+	}()
+	<-started
+
+	waiterDone := make(chan struct{})
+	var (
+		value int
+		err   error
+	)
+	go func() {
+		defer close(waiterDone)
+		value, err = m.get(context.Background(), "key", func(context.Context, string) (int, error) {
+			return 42, nil
+		})
+	}()
+
+	// Give the second caller time to start waiting on the first fetch
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-firstDone; err != context.Canceled {
+		t.Errorf("Expected context.Canceled for the first caller, got %v", err)
+	}
+
+	<-waiterDone
+	if err != nil {
+		t.Fatalf("Expected no error for the waiter, got %v", err)
+	}
+	if value != 42 {
+		t.Errorf("Expected 42, got %d", value)
+	}
+}
@@ -0,0 +1,323 @@
+package opendataug
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// merkleVersion is mixed into every hash so that changing how units are
+// hashed never produces hashes that collide with the old scheme
+const merkleVersion = "opendataug-merkle-v1"
+
+// Hash is a SHA-256 content hash
+type Hash [sha256.Size]byte
+
+// String returns the hash in hexadecimal
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (h *Hash) UnmarshalText(text []byte) error {
+	if hex.DecodedLen(len(text)) != len(h) {
+		return fmt.Errorf("opendataug: hash has %d characters, want %d", len(text), hex.EncodedLen(len(h)))
+	}
+	_, err := hex.Decode(h[:], text)
+	return err
+}
+
+// UnitHash returns the content hash of a single unit. It covers the level, ID,
+// name, code and parent of the unit, and the town status and region of
+// districts, but not the created and updated timestamps.
+func UnitHash(u AdminUnit) Hash {
+	h := sha256.New()
+	writeHashField(h, merkleVersion)
+	writeHashField(h, u.Level().String())
+	writeHashField(h, u.UnitID())
+	writeHashField(h, u.UnitName())
+	writeHashField(h, u.UnitCode())
+	writeHashField(h, u.ParentID())
+	if d, ok := u.(District); ok {
+		writeHashField(h, strconv.FormatBool(d.TownStatus))
+		writeHashField(h, d.RegionID)
+		writeHashField(h, d.RegionName)
+	}
+	return sumHash(h)
+}
+
+// merkleNode holds the hashes of one unit and the keys of its children
+type merkleNode struct {
+	unit     Hash
+	subtree  Hash
+	children []UnitKey
+}
+
+// MerkleTree holds the content hash of every unit linked into an AdminTree
+// and of the subtree below it. The hash of a subtree covers the unit and the
+// subtree hashes of its children sorted by ID, so two trees holding the same
+// units have the same hashes whatever order the units were loaded in. Units
+// that cannot be traced up to a district are not hashed.
+type MerkleTree struct {
+	root  Hash
+	roots []UnitKey
+	nodes map[UnitKey]merkleNode
+}
+
+// NewMerkleTree hashes every unit of tree
+func NewMerkleTree(tree *AdminTree) *MerkleTree {
+	type keyed struct {
+		key  UnitKey
+		hash Hash
+	}
+
+	m := &MerkleTree{nodes: make(map[UnitKey]merkleNode)}
+	sums := Aggregate(tree, func(u AdminUnit, children []keyed) keyed {
+		sortKeyed(children, func(c keyed) string { return c.key.ID })
+
+		node := merkleNode{unit: UnitHash(u), children: make([]UnitKey, len(children))}
+		h := sha256.New()
+		writeHashField(h, merkleVersion)
+		h.Write(node.unit[:])
+		for i, child := range children {
+			node.children[i] = child.key
+			h.Write(child.hash[:])
+		}
+		node.subtree = sumHash(h)
+
+		key := KeyOf(u)
+		m.nodes[key] = node
+		return keyed{key: key, hash: node.subtree}
+	})
+
+	var roots []keyed
+	for _, u := range tree.Roots() {
+		roots = append(roots, sums[KeyOf(u)])
+	}
+	sortKeyed(roots, func(r keyed) string { return r.key.ID })
+
+	h := sha256.New()
+	writeHashField(h, merkleVersion)
+	for _, r := range roots {
+		m.roots = append(m.roots, r.key)
+		h.Write(r.hash[:])
+	}
+	m.root = sumHash(h)
+
+	return m
+}
+
+// Root returns the hash of the whole hierarchy
+func (m *MerkleTree) Root() Hash {
+	return m.root
+}
+
+// Unit returns the content hash of a single unit
+func (m *MerkleTree) Unit(key UnitKey) (Hash, bool) {
+	node, ok := m.nodes[key]
+	return node.unit, ok
+}
+
+// Subtree returns the hash of a unit and everything below it
+func (m *MerkleTree) Subtree(key UnitKey) (Hash, bool) {
+	node, ok := m.nodes[key]
+	return node.subtree, ok
+}
+
+// Differences compares m with other from the top down and returns the units
+// whose branches differ. A unit is returned, without descending further, when
+// it exists on one side only, its own content differs or its set of children
+// differs. Otherwise only its children with differing subtrees are followed,
+// so identical branches are never visited. Keys are ordered depth-first by ID.
+func (m *MerkleTree) Differences(other *MerkleTree) []UnitKey {
+	if m.root == other.root {
+		return nil
+	}
+
+	var differences []UnitKey
+	var compare func(a, b []UnitKey)
+	compare = func(a, b []UnitKey) {
+		for _, key := range mergeKeys(a, b) {
+			na, okA := m.nodes[key]
+			nb, okB := other.nodes[key]
+			switch {
+			case !okA || !okB:
+				differences = append(differences, key)
+			case na.subtree == nb.subtree:
+			case na.unit != nb.unit || !slices.Equal(na.children, nb.children):
+				differences = append(differences, key)
+			default:
+				compare(na.children, nb.children)
+			}
+		}
+	}
+	compare(m.roots, other.roots)
+
+	return differences
+}
+
+// FetchBranches retrieves each unit in keys together with all of its
+// descendants, such as the branches returned by Differences. Branches are
+// crawled one level at a time, so each list is requested once. Units the API
+// no longer has are skipped, since their removal is the difference.
+func FetchBranches(ctx context.Context, api API, keys []UnitKey) (*Dataset, error) {
+	ds := &Dataset{}
+	limit := concurrencyLimit(api)
+
+	for _, key := range keys {
+		branch, err := fetchBranch(ctx, api, limit, key)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ds.Districts = append(ds.Districts, branch.Districts...)
+		ds.Counties = append(ds.Counties, branch.Counties...)
+		ds.Subcounties = append(ds.Subcounties, branch.Subcounties...)
+		ds.Parishes = append(ds.Parishes, branch.Parishes...)
+		ds.Villages = append(ds.Villages, branch.Villages...)
+	}
+
+	return ds, nil
+}
+
+func fetchBranch(ctx context.Context, api API, limit int, key UnitKey) (*Dataset, error) {
+	branch := &Dataset{}
+
+	switch key.Level {
+	case LevelDistrict:
+		id := DistrictID(key.ID)
+		district, err := api.GetDistrictContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		counties, err := api.GetCountiesByDistrictContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		branch.Districts = []District{*district}
+		return branch, branch.crawlCounties(ctx, api, limit, counties)
+
+	case LevelCounty:
+		county, err := api.GetCountyContext(ctx, CountyID(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		return branch, branch.crawlCounties(ctx, api, limit, []County{*county})
+
+	case LevelSubcounty:
+		subcounty, err := api.GetSubcountyContext(ctx, SubcountyID(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		return branch, branch.crawlSubcounties(ctx, api, limit, []Subcounty{*subcounty})
+
+	case LevelParish:
+		parish, err := api.GetParishContext(ctx, ParishID(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		return branch, branch.crawlParishes(ctx, api, limit, []Parish{*parish})
+
+	case LevelVillage:
+		village, err := api.GetVillageContext(ctx, VillageID(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		branch.Villages = []Village{*village}
+		return branch, nil
+	}
+
+	return nil, fmt.Errorf("opendataug: cannot fetch branch of invalid level %d", int(key.Level))
+}
+
+// crawlCounties adds counties to ds and crawls the levels below them, listing
+// the children of each unit once
+func (ds *Dataset) crawlCounties(ctx context.Context, api API, limit int, counties []County) error {
+	ds.Counties = append(ds.Counties, counties...)
+
+	subcounties, err := fanOut(ctx, limit, counties, func(ctx context.Context, county County) ([]Subcounty, error) {
+		return api.GetSubcountiesByCountyContext(ctx, county.ID)
+	})
+	if err != nil {
+		return err
+	}
+	return ds.crawlSubcounties(ctx, api, limit, subcounties)
+}
+
+func (ds *Dataset) crawlSubcounties(ctx context.Context, api API, limit int, subcounties []Subcounty) error {
+	ds.Subcounties = append(ds.Subcounties, subcounties...)
+
+	parishes, err := fanOut(ctx, limit, subcounties, func(ctx context.Context, subcounty Subcounty) ([]Parish, error) {
+		return api.GetParishesBySubcountyContext(ctx, subcounty.ID)
+	})
+	if err != nil {
+		return err
+	}
+	return ds.crawlParishes(ctx, api, limit, parishes)
+}
+
+func (ds *Dataset) crawlParishes(ctx context.Context, api API, limit int, parishes []Parish) error {
+	ds.Parishes = append(ds.Parishes, parishes...)
+
+	villages, err := fanOut(ctx, limit, parishes, func(ctx context.Context, parish Parish) ([]Village, error) {
+		return api.GetVillagesByParishContext(ctx, parish.ID)
+	})
+	if err != nil {
+		return err
+	}
+	ds.Villages = append(ds.Villages, villages...)
+	return nil
+}
+
+// writeHashField writes s to h prefixed with its length, so that adjacent
+// fields cannot run into each other
+func writeHashField(h hash.Hash, s string) {
+	var n [binary.MaxVarintLen64]byte
+	h.Write(n[:binary.PutUvarint(n[:], uint64(len(s)))])
+	h.Write([]byte(s))
+}
+
+func sumHash(h hash.Hash) Hash {
+	var sum Hash
+	h.Sum(sum[:0])
+	return sum
+}
+
+func sortKeyed[T any](items []T, id func(T) string) {
+	sort.SliceStable(items, func(i, j int) bool { return id(items[i]) < id(items[j]) })
+}
+
+// mergeKeys returns the keys in either sorted list, sorted by ID
+func mergeKeys(a, b []UnitKey) []UnitKey {
+	merged := make([]UnitKey, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i].ID < b[j].ID):
+			merged = append(merged, a[i])
+			i++
+		case i == len(a) || b[j].ID < a[i].ID:
+			merged = append(merged, b[j])
+			j++
+		default:
+			merged = append(merged, a[i])
+			i++
+			j++
+		}
+	}
+	return merged
+}
@@ -0,0 +1,240 @@
+package opendataug
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMerkleTreeDeterministic(t *testing.T) {
+	a := NewMerkleTree(NewAdminTree(testDataset()))
+
+	ds := testDataset()
+	slices.Reverse(ds.Districts)
+	slices.Reverse(ds.Parishes)
+	slices.Reverse(ds.Villages)
+	ds.Villages[0].UpdatedAt = NewTimestamp(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewMerkleTree(NewAdminTree(ds))
+
+	if a.Root() != b.Root() {
+		t.Errorf("Expected equal roots, got %s and %s", a.Root(), b.Root())
+	}
+	if diff := a.Differences(b); diff != nil {
+		t.Errorf("Expected no differences, got %v", diff)
+	}
+
+	key := UnitKey{Level: LevelParish, ID: "parish-1"}
+	unit, ok := a.Unit(key)
+	if !ok || unit != UnitHash(testDataset().Parishes[0]) {
+		t.Errorf("Expected the unit hash of parish-1, got %s", unit)
+	}
+	if subtree, _ := a.Subtree(key); subtree == unit {
+		t.Error("Expected the subtree hash to cover the children")
+	}
+
+	if _, ok := a.Unit(UnitKey{Level: LevelVillage, ID: "village-9"}); ok {
+		t.Error("Expected orphans not to be hashed")
+	}
+}
+
+func TestUnitHash(t *testing.T) {
+	base := County{ID: "county-1", Name: "Nakawa", Code: "NKW", DistrictID: "district-1"}
+
+	tests := []struct {
+		name   string
+		county County
+		equal  bool
+	}{
+		{name: "Same", county: base, equal: true},
+		{name: "Timestamps ignored", county: County{ID: "county-1", Name: "Nakawa", Code: "NKW", DistrictID: "district-1", UpdatedAt: NewTimestamp(time.Now())}, equal: true},
+		{name: "Renamed", county: County{ID: "county-1", Name: "Nakawa East", Code: "NKW", DistrictID: "district-1"}},
+		{name: "Fields shifted", county: County{ID: "county-1", Name: "NakawaNKW", DistrictID: "district-1"}},
+		{name: "Moved", county: County{ID: "county-1", Name: "Nakawa", Code: "NKW", DistrictID: "district-2"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := UnitHash(tc.county) == UnitHash(base); got != tc.equal {
+				t.Errorf("Expected equal to be %v, got %v", tc.equal, got)
+			}
+		})
+	}
+}
+
+func TestMerkleTreeDifferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(ds *Dataset)
+		expected []UnitKey
+	}{
+		{
+			name:     "Renamed village",
+			change:   func(ds *Dataset) { ds.Villages[2].Name = "Ntinda Market" },
+			expected: []UnitKey{{Level: LevelVillage, ID: "village-3"}},
+		},
+		{
+			name: "Added village",
+			change: func(ds *Dataset) {
+				ds.Villages = append(ds.Villages, Village{ID: "village-10", Name: "Kiwatule C", ParishID: "parish-1"})
+			},
+			expected: []UnitKey{{Level: LevelParish, ID: "parish-1"}},
+		},
+		{
+			name:     "Removed district",
+			change:   func(ds *Dataset) { ds.Districts = ds.Districts[:1] },
+			expected: []UnitKey{{Level: LevelDistrict, ID: "district-2"}},
+		},
+		{
+			name: "Changes in two branches",
+			change: func(ds *Dataset) {
+				ds.Counties[1].Code = "KWE"
+				ds.Villages[4].Name = "Gayaza"
+			},
+			expected: []UnitKey{
+				{Level: LevelCounty, ID: "county-2"},
+				{Level: LevelVillage, ID: "village-5"},
+			},
+		},
+	}
+
+	old := NewMerkleTree(NewAdminTree(testDataset()))
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ds := testDataset()
+			tc.change(ds)
+			got := old.Differences(NewMerkleTree(NewAdminTree(ds)))
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFetchBranches(t *testing.T) {
+	ds := testDataset()
+	ds.Villages = append(ds.Villages, Village{ID: "village-10", Name: "Kiwatule C", ParishID: "parish-1"})
+	api := NewOfflineClient(ds)
+
+	keys := []UnitKey{
+		{Level: LevelParish, ID: "parish-1"},
+		{Level: LevelDistrict, ID: "district-removed"},
+		{Level: LevelCounty, ID: "county-3"},
+	}
+	got, err := FetchBranches(context.Background(), api, keys)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := &Dataset{
+		Counties:    []County{ds.Counties[2]},
+		Subcounties: []Subcounty{ds.Subcounties[2]},
+		Parishes:    []Parish{ds.Parishes[0], ds.Parishes[3]},
+		Villages:    []Village{ds.Villages[0], ds.Villages[1], ds.Villages[6], ds.Villages[4]},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestFetchBranchesRequests(t *testing.T) {
+	// The server has no direct endpoints below the district
+	routes := map[string]string{
+		"/districts/district-1":             `{"data": {"id": "district-1"}}`,
+		"/districts/district-1/counties":    `{"data": [{"id": "county-1", "district_id": "district-1"}]}`,
+		"/counties/county-1":                `{"data": {"id": "county-1", "district_id": "district-1"}}`,
+		"/counties/county-1/subcounties":    `{"data": [{"id": "subcounty-1", "county_id": "county-1"}]}`,
+		"/subcounties/subcounty-1/parishes": `{"data": [{"id": "parish-1", "subcounty_id": "subcounty-1"}]}`,
+		"/parishes/parish-1/villages":       `{"data": [{"id": "village-1", "parish_id": "parish-1"}]}`,
+	}
+
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+
+		response, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "Not found"}`))
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+	baseURL = server.URL
+
+	tests := []struct {
+		name     string
+		key      UnitKey
+		expected []string
+	}{
+		{
+			name: "District",
+			key:  UnitKey{Level: LevelDistrict, ID: "district-1"},
+			expected: []string{
+				"/districts/district-1",
+				"/districts/district-1/counties",
+				"/counties/county-1/subcounties",
+				"/subcounties/subcounty-1/parishes",
+				"/parishes/parish-1/villages",
+			},
+		},
+		{
+			name: "County",
+			key:  UnitKey{Level: LevelCounty, ID: "county-1"},
+			expected: []string{
+				"/counties/county-1",
+				"/counties/county-1/subcounties",
+				"/subcounties/subcounty-1/parishes",
+				"/parishes/parish-1/villages",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			requests = nil
+
+			ds, err := FetchBranches(context.Background(), NewClient("test-api-key"), []UnitKey{tc.key})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(ds.Villages) != 1 || ds.Villages[0].ID != "village-1" {
+				t.Errorf("Expected village-1, got %+v", ds.Villages)
+			}
+			if !reflect.DeepEqual(requests, tc.expected) {
+				t.Errorf("Expected requests %v, got %v", tc.expected, requests)
+			}
+		})
+	}
+}
+
+func TestHashText(t *testing.T) {
+	h := UnitHash(testDataset().Districts[0])
+
+	text, err := h.MarshalText()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var decoded Hash
+	if err := decoded.UnmarshalText(text); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded != h {
+		t.Errorf("Expected %s, got %s", h, decoded)
+	}
+
+	if err := decoded.UnmarshalText([]byte("abc")); err == nil {
+		t.Error("Expected an error, got nil")
+	}
+}
@@ -2,51 +2,51 @@ package opendataug
 
 // District represents a district in Uganda
 type District struct {
-	ID         string `json:"id"`
-	Name       string `json:"name"`
-	TownStatus bool   `json:"town_status"`
-	RegionID   string `json:"region_id"`
-	RegionName string `json:"region_name"`
+	ID         DistrictID `json:"id"`
+	Name       string     `json:"name"`
+	TownStatus bool       `json:"town_status"`
+	RegionID   string     `json:"region_id"`
+	RegionName string     `json:"region_name"`
 }
 
 // County represents a county in Uganda
 type County struct {
-	ID         string `json:"id"`
-	Name       string `json:"name"`
-	Code       string `json:"code"`
-	DistrictID string `json:"district_id"`
-	CreatedAt  string `json:"created_at,omitempty"`
-	UpdatedAt  string `json:"updated_at,omitempty"`
+	ID         CountyID   `json:"id"`
+	Name       string     `json:"name"`
+	Code       string     `json:"code"`
+	DistrictID DistrictID `json:"district_id"`
+	CreatedAt  Timestamp  `json:"created_at,omitzero"`
+	UpdatedAt  Timestamp  `json:"updated_at,omitzero"`
 }
 
 // Subcounty represents a subcounty in Uganda
 type Subcounty struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Code      string `json:"code"`
-	CountyID  string `json:"county_id"`
-	CreatedAt string `json:"created_at,omitempty"`
-	UpdatedAt string `json:"updated_at,omitempty"`
+	ID        SubcountyID `json:"id"`
+	Name      string      `json:"name"`
+	Code      string      `json:"code"`
+	CountyID  CountyID    `json:"county_id"`
+	CreatedAt Timestamp   `json:"created_at,omitzero"`
+	UpdatedAt Timestamp   `json:"updated_at,omitzero"`
 }
 
 // Parish represents a parish in Uganda
 type Parish struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Code        string `json:"code"`
-	SubcountyID string `json:"subcounty_id"`
-	CreatedAt   string `json:"created_at,omitempty"`
-	UpdatedAt   string `json:"updated_at,omitempty"`
+	ID          ParishID    `json:"id"`
+	Name        string      `json:"name"`
+	Code        string      `json:"code"`
+	SubcountyID SubcountyID `json:"subcounty_id"`
+	CreatedAt   Timestamp   `json:"created_at,omitzero"`
+	UpdatedAt   Timestamp   `json:"updated_at,omitzero"`
 }
 
 // Village represents a village in Uganda
 type Village struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Code      string `json:"code"`
-	ParishID  string `json:"parish_id"`
-	CreatedAt string `json:"created_at,omitempty"`
-	UpdatedAt string `json:"updated_at,omitempty"`
+	ID        VillageID `json:"id"`
+	Name      string    `json:"name"`
+	Code      string    `json:"code"`
+	ParishID  ParishID  `json:"parish_id"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 }
 
 // Response is a generic response wrapper
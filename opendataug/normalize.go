@@ -0,0 +1,200 @@
+package opendataug
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// NormalizationRules configure a Normalizer. They can be loaded from JSON so
+// that a rule set can be versioned alongside the data it was tuned on.
+type NormalizationRules struct {
+	// Version identifies the rule set. Keys produced by different versions
+	// should not be compared.
+	Version string `json:"version"`
+	// Abbreviations maps lower-case abbreviations to the words they stand
+	// for. Abbreviations written with a slash or dots, such as "t/c" or
+	// "t.c.", are matched with the punctuation removed ("tc").
+	Abbreviations map[string]string `json:"abbreviations"`
+	// Qualifiers are lower-case words and phrases that describe the kind of
+	// unit rather than name it. They are dropped from comparison keys but
+	// kept in display names.
+	Qualifiers []string `json:"qualifiers"`
+}
+
+// DefaultNormalizationRules returns the rule set used by DefaultNormalizer.
+// Each call returns a fresh copy, so callers can extend it without affecting
+// DefaultNormalizer or each other.
+func DefaultNormalizationRules() NormalizationRules {
+	return NormalizationRules{
+		Version: "1",
+		Abbreviations: map[string]string{
+			"div":  "division",
+			"mun":  "municipality",
+			"muni": "municipality",
+			"mc":   "municipal council",
+			"tc":   "town council",
+			"sc":   "subcounty",
+			"dist": "district",
+		},
+		Qualifiers: []string{
+			"district",
+			"county",
+			"sub county",
+			"subcounty",
+			"parish",
+			"village",
+			"ward",
+			"cell",
+			"division",
+			"municipality",
+			"municipal council",
+			"town council",
+			"town",
+			"city",
+		},
+	}
+}
+
+// DefaultNormalizer normalises names with DefaultNormalizationRules
+var DefaultNormalizer = NewNormalizer(DefaultNormalizationRules())
+
+// NormalizedName holds the normalised forms of a unit's name
+type NormalizedName struct {
+	// Key is the canonical comparison key of the name
+	Key string `json:"key"`
+	// Display is the name with abbreviations expanded and consistent
+	// capitalisation
+	Display string `json:"display"`
+	// Version is the version of the rules that produced the name
+	Version string `json:"version"`
+}
+
+// Normalizer produces canonical comparison keys and display names for place
+// names. It is safe for concurrent use.
+type Normalizer struct {
+	version       string
+	abbreviations map[string][]string
+	qualifiers    [][]string
+}
+
+// punctuatedAbbreviations matches abbreviations such as "T/C" and "S.C."
+// whose punctuation would otherwise split them into separate words
+var punctuatedAbbreviations = regexp.MustCompile(`(?i)\b([a-z])\s*[/.]\s*([a-z])\b\.?`)
+
+// NewNormalizer returns a Normalizer applying rules
+func NewNormalizer(rules NormalizationRules) *Normalizer {
+	n := &Normalizer{
+		version:       rules.Version,
+		abbreviations: make(map[string][]string, len(rules.Abbreviations)),
+	}
+
+	for abbreviation, expansion := range rules.Abbreviations {
+		n.abbreviations[strings.ToLower(abbreviation)] = splitWords(strings.ToLower(expansion))
+	}
+	for _, qualifier := range rules.Qualifiers {
+		if words := splitWords(strings.ToLower(qualifier)); len(words) > 0 {
+			n.qualifiers = append(n.qualifiers, words)
+		}
+	}
+
+	return n
+}
+
+// Version returns the version of the rules applied by n
+func (n *Normalizer) Version() string {
+	return n.version
+}
+
+// Key returns the canonical comparison key of name: lower-case words with
+// abbreviations expanded and qualifiers removed, so that "Kawempe Division"
+// and "KAWEMPE DIV." share a key. Names made up only of qualifiers have an
+// empty key.
+func (n *Normalizer) Key(name string) string {
+	words := n.expand(strings.ToLower(name))
+
+	var kept []string
+	for i := 0; i < len(words); {
+		if length := n.qualifierAt(words, i); length > 0 {
+			i += length
+			continue
+		}
+		kept = append(kept, words[i])
+		i++
+	}
+
+	return strings.Join(kept, " ")
+}
+
+// DisplayName returns name with abbreviations expanded, whitespace collapsed
+// and each word capitalised, so "kyotera  t/c" becomes "Kyotera Town
+// Council". Words the rules do not know keep their punctuation.
+func (n *Normalizer) DisplayName(name string) string {
+	name = punctuatedAbbreviations.ReplaceAllString(name, "$1$2")
+
+	var words []string
+	for _, word := range strings.Fields(name) {
+		trimmed := strings.ToLower(strings.Trim(word, ".,"))
+		if expansion, ok := n.abbreviations[trimmed]; ok {
+			for _, w := range expansion {
+				words = append(words, titleWord(w))
+			}
+			continue
+		}
+		words = append(words, titleWord(word))
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Normalize returns the normalised forms of the name of u
+func (n *Normalizer) Normalize(u AdminUnit) NormalizedName {
+	return NormalizedName{
+		Key:     n.Key(u.UnitName()),
+		Display: n.DisplayName(u.UnitName()),
+		Version: n.version,
+	}
+}
+
+// expand splits lower-case s into words and expands abbreviations
+func (n *Normalizer) expand(s string) []string {
+	s = punctuatedAbbreviations.ReplaceAllString(s, "$1$2")
+
+	var words []string
+	for _, word := range splitWords(s) {
+		if expansion, ok := n.abbreviations[word]; ok {
+			words = append(words, expansion...)
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// qualifierAt returns the number of words of the longest qualifier starting at
+// words[i], or 0 if none does
+func (n *Normalizer) qualifierAt(words []string, i int) int {
+	longest := 0
+	for _, qualifier := range n.qualifiers {
+		if len(qualifier) <= longest || i+len(qualifier) > len(words) {
+			continue
+		}
+		match := true
+		for j, w := range qualifier {
+			if words[i+j] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			longest = len(qualifier)
+		}
+	}
+	return longest
+}
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
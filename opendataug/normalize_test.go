@@ -0,0 +1,129 @@
+package opendataug
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNormalizerKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "Kawempe Division", expected: "kawempe"},
+		{input: "kawempe div", expected: "kawempe"},
+		{input: "KAWEMPE DIV.", expected: "kawempe"},
+		{input: "Kyotera T/C", expected: "kyotera"},
+		{input: "Kyotera T.C.", expected: "kyotera"},
+		{input: "Kyotera Town Council", expected: "kyotera"},
+		{input: "Mbarara Municipality", expected: "mbarara"},
+		{input: "Kira Municipal Council", expected: "kira"},
+		{input: "Nangabo Sub County", expected: "nangabo"},
+		{input: "Nangabo S/C", expected: "nangabo"},
+		{input: "Kiwatule  Ward", expected: "kiwatule"},
+		{input: "Kiwatule A", expected: "kiwatule a"},
+		{input: "Ntinda-Kigoowa", expected: "ntinda kigoowa"},
+		{input: "District", expected: ""},
+		{input: "", expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			if key := DefaultNormalizer.Key(tc.input); key != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, key)
+			}
+		})
+	}
+}
+
+func TestNormalizerDisplayName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "KAWEMPE DIV", expected: "Kawempe Division"},
+		{input: "kyotera  t/c", expected: "Kyotera Town Council"},
+		{input: "Kyotera T.C.", expected: "Kyotera Town Council"},
+		{input: "mbarara mun.", expected: "Mbarara Municipality"},
+		{input: "ntinda-kigoowa II", expected: "Ntinda-Kigoowa II"},
+		{input: " kiwatule ", expected: "Kiwatule"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			if name := DefaultNormalizer.DisplayName(tc.input); name != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, name)
+			}
+		})
+	}
+}
+
+func TestNormalizerCustomRules(t *testing.T) {
+	var rules NormalizationRules
+	err := json.Unmarshal([]byte(`{
+		"version": "test-2",
+		"abbreviations": {"hq": "headquarters"},
+		"qualifiers": ["headquarters", "trading centre"]
+	}`), &rules)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	n := NewNormalizer(rules)
+
+	tests := []struct {
+		name     string
+		unit     AdminUnit
+		expected NormalizedName
+	}{
+		{
+			name:     "Village",
+			unit:     Village{ID: "village-5", Name: "gayaza trading centre"},
+			expected: NormalizedName{Key: "gayaza", Display: "Gayaza Trading Centre", Version: "test-2"},
+		},
+		{
+			name:     "District",
+			unit:     District{ID: "district-1", Name: "Kampala HQ"},
+			expected: NormalizedName{Key: "kampala", Display: "Kampala Headquarters", Version: "test-2"},
+		},
+		{
+			name:     "Subcounty",
+			unit:     Subcounty{ID: "subcounty-1", Name: "Nakawa Division"},
+			expected: NormalizedName{Key: "nakawa division", Display: "Nakawa Division", Version: "test-2"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if normalized := n.Normalize(tc.unit); normalized != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, normalized)
+			}
+		})
+	}
+
+	if n.Version() != "test-2" {
+		t.Errorf("Expected version test-2, got %s", n.Version())
+	}
+}
+
+func TestDefaultNormalizationRulesCopy(t *testing.T) {
+	rules := DefaultNormalizationRules()
+	rules.Abbreviations["hq"] = "headquarters"
+	rules.Qualifiers = append(rules.Qualifiers[:0], "headquarters")
+
+	n := NewNormalizer(rules)
+	if key := n.Key("Kampala HQ"); key != "kampala" {
+		t.Errorf("Expected kampala, got %s", key)
+	}
+
+	fresh := DefaultNormalizationRules()
+	if _, ok := fresh.Abbreviations["hq"]; ok {
+		t.Error("Expected the default abbreviations to be unaffected")
+	}
+	if fresh.Qualifiers[0] != "district" {
+		t.Errorf("Expected the default qualifiers to be unaffected, got %v", fresh.Qualifiers)
+	}
+	if key := DefaultNormalizer.Key("Kampala District"); key != "kampala" {
+		t.Errorf("Expected kampala, got %s", key)
+	}
+}
@@ -0,0 +1,407 @@
+package opendataug
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// OfflineClient serves the API from a dataset held in memory, such as one
+// read from a snapshot. Lookups of unknown IDs fail with the same 404
+// APIError the live API returns.
+type OfflineClient struct {
+	tree *AdminTree
+}
+
+// NewOfflineClient returns an OfflineClient serving the units in ds
+func NewOfflineClient(ds *Dataset) *OfflineClient {
+	return &OfflineClient{tree: NewAdminTree(ds)}
+}
+
+// LoadOfflineClient returns an OfflineClient serving the snapshot in dir
+func LoadOfflineClient(dir string) (*OfflineClient, error) {
+	ds, _, err := ReadSnapshot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOfflineClient(ds), nil
+}
+
+// Tree returns the hierarchy served by the client
+func (o *OfflineClient) Tree() *AdminTree {
+	return o.tree
+}
+
+// errOfflineNotFound mirrors the error the live API returns for unknown IDs
+func errOfflineNotFound() error {
+	return &APIError{StatusCode: http.StatusNotFound, Message: "Not found"}
+}
+
+// found returns a pointer to unit, or a not found error when ok is false
+func found[T any](unit T, ok bool) (*T, error) {
+	if !ok {
+		return nil, errOfflineNotFound()
+	}
+	return &unit, nil
+}
+
+// GetDistricts retrieves all districts
+func (o *OfflineClient) GetDistricts() ([]District, error) {
+	return o.GetDistrictsContext(context.Background())
+}
+
+// GetDistrictsContext retrieves all districts using ctx
+func (o *OfflineClient) GetDistrictsContext(ctx context.Context) ([]District, error) {
+	return o.tree.Districts(), ctx.Err()
+}
+
+// GetDistrict retrieves a specific district by ID
+func (o *OfflineClient) GetDistrict(id DistrictID) (*District, error) {
+	return o.GetDistrictContext(context.Background(), id)
+}
+
+// GetDistrictContext retrieves a specific district by ID using ctx
+func (o *OfflineClient) GetDistrictContext(ctx context.Context, id DistrictID) (*District, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return found(o.tree.District(id))
+}
+
+// GetCounties retrieves all counties
+func (o *OfflineClient) GetCounties() ([]County, error) {
+	return o.GetCountiesContext(context.Background())
+}
+
+// GetCountiesContext retrieves all counties using ctx
+func (o *OfflineClient) GetCountiesContext(ctx context.Context) ([]County, error) {
+	return o.tree.Counties(), ctx.Err()
+}
+
+// GetCounty retrieves a specific county by ID
+func (o *OfflineClient) GetCounty(id CountyID) (*County, error) {
+	return o.GetCountyContext(context.Background(), id)
+}
+
+// GetCountyContext retrieves a specific county by ID using ctx
+func (o *OfflineClient) GetCountyContext(ctx context.Context, id CountyID) (*County, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return found(o.tree.County(id))
+}
+
+// GetCountiesByDistrict retrieves all counties in a specific district
+func (o *OfflineClient) GetCountiesByDistrict(districtID DistrictID) ([]County, error) {
+	return o.GetCountiesByDistrictContext(context.Background(), districtID)
+}
+
+// GetCountiesByDistrictContext retrieves all counties in a specific district using ctx
+func (o *OfflineClient) GetCountiesByDistrictContext(ctx context.Context, districtID DistrictID) ([]County, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, ok := o.tree.District(districtID); !ok {
+		return nil, errOfflineNotFound()
+	}
+	return o.tree.CountiesOf(districtID), nil
+}
+
+// GetSubcounties retrieves all subcounties
+func (o *OfflineClient) GetSubcounties() ([]Subcounty, error) {
+	return o.GetSubcountiesContext(context.Background())
+}
+
+// GetSubcountiesContext retrieves all subcounties using ctx
+func (o *OfflineClient) GetSubcountiesContext(ctx context.Context) ([]Subcounty, error) {
+	return o.tree.Subcounties(), ctx.Err()
+}
+
+// GetSubcounty retrieves a specific subcounty by ID
+func (o *OfflineClient) GetSubcounty(id SubcountyID) (*Subcounty, error) {
+	return o.GetSubcountyContext(context.Background(), id)
+}
+
+// GetSubcountyContext retrieves a specific subcounty by ID using ctx
+func (o *OfflineClient) GetSubcountyContext(ctx context.Context, id SubcountyID) (*Subcounty, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return found(o.tree.Subcounty(id))
+}
+
+// GetSubcountiesByCounty retrieves all subcounties in a specific county
+func (o *OfflineClient) GetSubcountiesByCounty(countyID CountyID) ([]Subcounty, error) {
+	return o.GetSubcountiesByCountyContext(context.Background(), countyID)
+}
+
+// GetSubcountiesByCountyContext retrieves all subcounties in a specific county using ctx
+func (o *OfflineClient) GetSubcountiesByCountyContext(ctx context.Context, countyID CountyID) ([]Subcounty, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, ok := o.tree.County(countyID); !ok {
+		return nil, errOfflineNotFound()
+	}
+	return o.tree.SubcountiesOf(countyID), nil
+}
+
+// GetSubcountiesByDistrict retrieves all subcounties in a specific district
+func (o *OfflineClient) GetSubcountiesByDistrict(ctx context.Context, districtID DistrictID) ([]Subcounty, error) {
+	counties, err := o.GetCountiesByDistrictContext(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+
+	subcounties := []Subcounty{}
+	for _, county := range counties {
+		subcounties = append(subcounties, o.tree.SubcountiesOf(county.ID)...)
+	}
+	return subcounties, ctx.Err()
+}
+
+// GetParishes retrieves all parishes
+func (o *OfflineClient) GetParishes() ([]Parish, error) {
+	return o.GetParishesContext(context.Background())
+}
+
+// GetParishesContext retrieves all parishes using ctx
+func (o *OfflineClient) GetParishesContext(ctx context.Context) ([]Parish, error) {
+	return o.tree.Parishes(), ctx.Err()
+}
+
+// GetParish retrieves a specific parish by ID
+func (o *OfflineClient) GetParish(id ParishID) (*Parish, error) {
+	return o.GetParishContext(context.Background(), id)
+}
+
+// GetParishContext retrieves a specific parish by ID using ctx
+func (o *OfflineClient) GetParishContext(ctx context.Context, id ParishID) (*Parish, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return found(o.tree.Parish(id))
+}
+
+// GetParishesBySubcounty retrieves all parishes in a specific subcounty
+func (o *OfflineClient) GetParishesBySubcounty(subcountyID SubcountyID) ([]Parish, error) {
+	return o.GetParishesBySubcountyContext(context.Background(), subcountyID)
+}
+
+// GetParishesBySubcountyContext retrieves all parishes in a specific subcounty using ctx
+func (o *OfflineClient) GetParishesBySubcountyContext(ctx context.Context, subcountyID SubcountyID) ([]Parish, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, ok := o.tree.Subcounty(subcountyID); !ok {
+		return nil, errOfflineNotFound()
+	}
+	return o.tree.ParishesOf(subcountyID), nil
+}
+
+// GetParishesByCounty retrieves all parishes in a specific county
+func (o *OfflineClient) GetParishesByCounty(ctx context.Context, countyID CountyID) ([]Parish, error) {
+	subcounties, err := o.GetSubcountiesByCountyContext(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+
+	parishes := []Parish{}
+	for _, subcounty := range subcounties {
+		parishes = append(parishes, o.tree.ParishesOf(subcounty.ID)...)
+	}
+	return parishes, ctx.Err()
+}
+
+// GetParishesByDistrict retrieves all parishes in a specific district
+func (o *OfflineClient) GetParishesByDistrict(ctx context.Context, districtID DistrictID) ([]Parish, error) {
+	subcounties, err := o.GetSubcountiesByDistrict(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+
+	parishes := []Parish{}
+	for _, subcounty := range subcounties {
+		parishes = append(parishes, o.tree.ParishesOf(subcounty.ID)...)
+	}
+	return parishes, ctx.Err()
+}
+
+// GetVillages retrieves all villages
+func (o *OfflineClient) GetVillages() ([]Village, error) {
+	return o.GetVillagesContext(context.Background())
+}
+
+// GetVillagesContext retrieves all villages using ctx
+func (o *OfflineClient) GetVillagesContext(ctx context.Context) ([]Village, error) {
+	return o.tree.Villages(), ctx.Err()
+}
+
+// GetVillage retrieves a specific village by ID
+func (o *OfflineClient) GetVillage(id VillageID) (*Village, error) {
+	return o.GetVillageContext(context.Background(), id)
+}
+
+// GetVillageContext retrieves a specific village by ID using ctx
+func (o *OfflineClient) GetVillageContext(ctx context.Context, id VillageID) (*Village, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return found(o.tree.Village(id))
+}
+
+// GetVillagesByParish retrieves all villages in a specific parish
+func (o *OfflineClient) GetVillagesByParish(parishID ParishID) ([]Village, error) {
+	return o.GetVillagesByParishContext(context.Background(), parishID)
+}
+
+// GetVillagesByParishContext retrieves all villages in a specific parish using ctx
+func (o *OfflineClient) GetVillagesByParishContext(ctx context.Context, parishID ParishID) ([]Village, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, ok := o.tree.Parish(parishID); !ok {
+		return nil, errOfflineNotFound()
+	}
+	return o.tree.VillagesOf(parishID), nil
+}
+
+// GetVillagesBySubcounty retrieves all villages in a specific subcounty
+func (o *OfflineClient) GetVillagesBySubcounty(ctx context.Context, subcountyID SubcountyID) ([]Village, error) {
+	parishes, err := o.GetParishesBySubcountyContext(ctx, subcountyID)
+	if err != nil {
+		return nil, err
+	}
+	return o.villagesOf(ctx, parishes)
+}
+
+// GetVillagesByCounty retrieves all villages in a specific county
+func (o *OfflineClient) GetVillagesByCounty(ctx context.Context, countyID CountyID) ([]Village, error) {
+	parishes, err := o.GetParishesByCounty(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	return o.villagesOf(ctx, parishes)
+}
+
+// GetVillagesByDistrict retrieves all villages in a specific district
+func (o *OfflineClient) GetVillagesByDistrict(ctx context.Context, districtID DistrictID) ([]Village, error) {
+	parishes, err := o.GetParishesByDistrict(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+	return o.villagesOf(ctx, parishes)
+}
+
+func (o *OfflineClient) villagesOf(ctx context.Context, parishes []Parish) ([]Village, error) {
+	villages := []Village{}
+	for _, parish := range parishes {
+		villages = append(villages, o.tree.VillagesOf(parish.ID)...)
+	}
+	return villages, ctx.Err()
+}
+
+// GetCountyByCode retrieves a specific county by its code
+func (o *OfflineClient) GetCountyByCode(ctx context.Context, code string) (*County, error) {
+	return matchCode(o.tree.Counties(), "county", code, func(county County) string {
+		return county.Code
+	})
+}
+
+// GetCountyByCodeInDistrict retrieves a county by its code within a specific district
+func (o *OfflineClient) GetCountyByCodeInDistrict(ctx context.Context, districtID DistrictID, code string) (*County, error) {
+	counties, err := o.GetCountiesByDistrictContext(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+	return matchCode(counties, "county", code, func(county County) string {
+		return county.Code
+	})
+}
+
+// GetSubcountyByCode retrieves a specific subcounty by its code
+func (o *OfflineClient) GetSubcountyByCode(ctx context.Context, code string) (*Subcounty, error) {
+	return matchCode(o.tree.Subcounties(), "subcounty", code, func(subcounty Subcounty) string {
+		return subcounty.Code
+	})
+}
+
+// GetSubcountyByCodeInCounty retrieves a subcounty by its code within a specific county
+func (o *OfflineClient) GetSubcountyByCodeInCounty(ctx context.Context, countyID CountyID, code string) (*Subcounty, error) {
+	subcounties, err := o.GetSubcountiesByCountyContext(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	return matchCode(subcounties, "subcounty", code, func(subcounty Subcounty) string {
+		return subcounty.Code
+	})
+}
+
+// GetParishByCode retrieves a specific parish by its code
+func (o *OfflineClient) GetParishByCode(ctx context.Context, code string) (*Parish, error) {
+	return matchCode(o.tree.Parishes(), "parish", code, func(parish Parish) string {
+		return parish.Code
+	})
+}
+
+// GetParishByCodeInSubcounty retrieves a parish by its code within a specific subcounty
+func (o *OfflineClient) GetParishByCodeInSubcounty(ctx context.Context, subcountyID SubcountyID, code string) (*Parish, error) {
+	parishes, err := o.GetParishesBySubcountyContext(ctx, subcountyID)
+	if err != nil {
+		return nil, err
+	}
+	return matchCode(parishes, "parish", code, func(parish Parish) string {
+		return parish.Code
+	})
+}
+
+// GetVillageByCode retrieves a specific village by its code
+func (o *OfflineClient) GetVillageByCode(ctx context.Context, code string) (*Village, error) {
+	return matchCode(o.tree.Villages(), "village", code, func(village Village) string {
+		return village.Code
+	})
+}
+
+// GetVillageByCodeInParish retrieves a village by its code within a specific parish
+func (o *OfflineClient) GetVillageByCodeInParish(ctx context.Context, parishID ParishID, code string) (*Village, error) {
+	villages, err := o.GetVillagesByParishContext(ctx, parishID)
+	if err != nil {
+		return nil, err
+	}
+	return matchCode(villages, "village", code, func(village Village) string {
+		return village.Code
+	})
+}
+
+// GetCountiesUpdatedSince retrieves all counties updated at or after since
+func (o *OfflineClient) GetCountiesUpdatedSince(ctx context.Context, since time.Time) ([]County, time.Time, error) {
+	counties, mark := filterUpdatedSince(o.tree.Counties(), since, func(county County) Timestamp {
+		return county.UpdatedAt
+	})
+	return counties, mark, nil
+}
+
+// GetSubcountiesUpdatedSince retrieves all subcounties updated at or after since
+func (o *OfflineClient) GetSubcountiesUpdatedSince(ctx context.Context, since time.Time) ([]Subcounty, time.Time, error) {
+	subcounties, mark := filterUpdatedSince(o.tree.Subcounties(), since, func(subcounty Subcounty) Timestamp {
+		return subcounty.UpdatedAt
+	})
+	return subcounties, mark, nil
+}
+
+// GetParishesUpdatedSince retrieves all parishes updated at or after since
+func (o *OfflineClient) GetParishesUpdatedSince(ctx context.Context, since time.Time) ([]Parish, time.Time, error) {
+	parishes, mark := filterUpdatedSince(o.tree.Parishes(), since, func(parish Parish) Timestamp {
+		return parish.UpdatedAt
+	})
+	return parishes, mark, nil
+}
+
+// GetVillagesUpdatedSince retrieves all villages updated at or after since
+func (o *OfflineClient) GetVillagesUpdatedSince(ctx context.Context, since time.Time) ([]Village, time.Time, error) {
+	villages, mark := filterUpdatedSince(o.tree.Villages(), since, func(village Village) Timestamp {
+		return village.UpdatedAt
+	})
+	return villages, mark, nil
+}
@@ -0,0 +1,215 @@
+package opendataug
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOfflineClientLookups(t *testing.T) {
+	ctx := context.Background()
+	var api API = NewOfflineClient(testDataset())
+
+	district, err := api.GetDistrictContext(ctx, "district-1")
+	if err != nil || district.Name != "Kampala" {
+		t.Errorf("Expected Kampala, got %+v, %v", district, err)
+	}
+
+	village, err := api.GetVillageContext(ctx, "village-9")
+	if err != nil || village.Name != "Lost Village" {
+		t.Errorf("Expected the orphan village, got %+v, %v", village, err)
+	}
+
+	county, err := api.GetCountyByCodeInDistrict(ctx, "district-1", "kwp")
+	if err != nil || county.ID != "county-2" {
+		t.Errorf("Expected county-2, got %+v, %v", county, err)
+	}
+
+	if _, err := api.GetParishByCode(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	var apiErr *APIError
+	if _, err := api.GetParishContext(ctx, "parish-missing"); !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		t.Errorf("Expected a 404 APIError, got %v", err)
+	}
+	if _, err := api.GetVillagesByParishContext(ctx, "parish-missing"); !isNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestOfflineClientDescendants(t *testing.T) {
+	ctx := context.Background()
+	o := NewOfflineClient(testDataset())
+
+	tests := []struct {
+		name     string
+		get      func() ([]AdminUnit, error)
+		expected []string
+	}{
+		{
+			name: "Counties by district",
+			get: func() ([]AdminUnit, error) {
+				counties, err := o.GetCountiesByDistrict("district-1")
+				return units(counties), err
+			},
+			expected: []string{"county-1", "county-2"},
+		},
+		{
+			name: "Subcounties by district",
+			get: func() ([]AdminUnit, error) {
+				subcounties, err := o.GetSubcountiesByDistrict(ctx, "district-1")
+				return units(subcounties), err
+			},
+			expected: []string{"subcounty-1", "subcounty-2"},
+		},
+		{
+			name: "Parishes by county",
+			get: func() ([]AdminUnit, error) {
+				parishes, err := o.GetParishesByCounty(ctx, "county-1")
+				return units(parishes), err
+			},
+			expected: []string{"parish-1", "parish-2"},
+		},
+		{
+			name: "Villages by district",
+			get: func() ([]AdminUnit, error) {
+				villages, err := o.GetVillagesByDistrict(ctx, "district-1")
+				return units(villages), err
+			},
+			expected: []string{"village-1", "village-2", "village-3", "village-4"},
+		},
+		{
+			name: "Villages by subcounty",
+			get: func() ([]AdminUnit, error) {
+				villages, err := o.GetVillagesBySubcounty(ctx, "subcounty-3")
+				return units(villages), err
+			},
+			expected: []string{"village-5"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.get()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if ids := unitIDs(got); !reflect.DeepEqual(ids, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, ids)
+			}
+		})
+	}
+}
+
+func TestOfflineClientUpdatedSince(t *testing.T) {
+	ds := testDataset()
+	ds.Parishes[0].UpdatedAt = NewTimestamp(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ds.Parishes[1].UpdatedAt = NewTimestamp(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	ds.Parishes[2].UpdatedAt = NewTimestamp(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+	ds.Parishes[3].UpdatedAt = NewTimestamp(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	o := NewOfflineClient(ds)
+	since := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	parishes, mark, err := o.GetParishesUpdatedSince(context.Background(), since)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if ids := unitIDs(units(parishes)); !reflect.DeepEqual(ids, []string{"parish-1", "parish-2"}) {
+		t.Errorf("Expected parish-1 and parish-2, got %v", ids)
+	}
+	if !mark.Equal(ds.Parishes[1].UpdatedAt.Time) {
+		t.Errorf("Expected high-water mark %v, got %v", ds.Parishes[1].UpdatedAt, mark)
+	}
+}
+
+func TestLoadOfflineClient(t *testing.T) {
+	ctx := context.Background()
+	ds := testDataset()
+	server, client := TestRoutesServer(t, datasetRoutes(t, ds))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if _, err := WriteSnapshot(ctx, client, dir); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	offline, err := LoadOfflineClient(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Both backends serve the same lists through the API interface
+	for _, api := range []API{client, offline} {
+		districts, err := api.GetDistrictsContext(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(districts, ds.Districts) {
+			t.Errorf("Expected %+v, got %+v", ds.Districts, districts)
+		}
+
+		villages, err := api.GetVillagesContext(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(villages, ds.Villages) {
+			t.Errorf("Expected %+v, got %+v", ds.Villages, villages)
+		}
+	}
+}
+
+func TestOfflineClientEntryPoints(t *testing.T) {
+	ctx := context.Background()
+	ds := testDataset()
+	offline := NewOfflineClient(ds)
+
+	tree, err := BuildTree(ctx, offline)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if tree.Counts() != NewAdminTree(ds).Counts() {
+		t.Errorf("Expected counts %+v, got %+v", NewAdminTree(ds).Counts(), tree.Counts())
+	}
+
+	report, err := Validate(ctx, offline)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(report, ValidateDataset(ds)) {
+		t.Errorf("Expected %+v, got %+v", ValidateDataset(ds), report)
+	}
+
+	if _, err := ComputeStatistics(ctx, offline); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	paths, err := NewPathResolver(offline).ResolveVillagePaths(ctx, []VillageID{"village-1", "village-missing"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(paths) != 1 || paths["village-1"].District.Name != "Kampala" {
+		t.Errorf("Expected the path of village-1 only, got %+v", paths)
+	}
+
+	dir := t.TempDir()
+	if _, err := WriteSnapshot(ctx, offline, dir); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got, _, err := ReadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got, ds) {
+		t.Errorf("Expected %+v, got %+v", ds, got)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := offline.GetDistrictContext(cancelled, "district-1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
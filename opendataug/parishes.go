@@ -1,32 +1,34 @@
 package opendataug
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
 
 // GetParishes retrieves all parishes
 func (c *Client) GetParishes() ([]Parish, error) {
-	var response struct {
-		Data []Parish `json:"data"`
-	}
-
-	err := c.doRequest(http.MethodGet, "/parishes", &response)
-	if err != nil {
-		return nil, err
-	}
+	return c.GetParishesContext(context.Background())
+}
 
-	return response.Data, nil
+// GetParishesContext retrieves all parishes using ctx
+func (c *Client) GetParishesContext(ctx context.Context) ([]Parish, error) {
+	return getList[Parish](ctx, c, "/parishes")
 }
 
 // GetParish retrieves a specific parish by ID
-func (c *Client) GetParish(id string) (*Parish, error) {
+func (c *Client) GetParish(id ParishID) (*Parish, error) {
+	return c.GetParishContext(context.Background(), id)
+}
+
+// GetParishContext retrieves a specific parish by ID using ctx
+func (c *Client) GetParishContext(ctx context.Context, id ParishID) (*Parish, error) {
 	var response struct {
 		Data Parish `json:"data"`
 	}
 
 	path := fmt.Sprintf("/parishes/%s", id)
-	err := c.doRequest(http.MethodGet, path, &response)
+	err := c.doRequestContext(ctx, http.MethodGet, path, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -35,16 +37,12 @@ func (c *Client) GetParish(id string) (*Parish, error) {
 }
 
 // GetParishesBySubcounty retrieves all parishes in a specific subcounty
-func (c *Client) GetParishesBySubcounty(subcountyID string) ([]Parish, error) {
-	var response struct {
-		Data []Parish `json:"data"`
-	}
+func (c *Client) GetParishesBySubcounty(subcountyID SubcountyID) ([]Parish, error) {
+	return c.GetParishesBySubcountyContext(context.Background(), subcountyID)
+}
 
+// GetParishesBySubcountyContext retrieves all parishes in a specific subcounty using ctx
+func (c *Client) GetParishesBySubcountyContext(ctx context.Context, subcountyID SubcountyID) ([]Parish, error) {
 	path := fmt.Sprintf("/subcounties/%s/parishes", subcountyID)
-	err := c.doRequest(http.MethodGet, path, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return response.Data, nil
+	return getList[Parish](ctx, c, path)
 }
@@ -55,7 +55,7 @@ func TestGetParishes(t *testing.T) {
 func TestGetParish(t *testing.T) {
 	tests := []struct {
 		name           string
-		parishID       string
+		parishID       ParishID
 		expectedPath   string
 		response       string
 		expectedResult *Parish
@@ -118,7 +118,7 @@ func TestGetParish(t *testing.T) {
 func TestGetParishesBySubcounty(t *testing.T) {
 	tests := []struct {
 		name           string
-		subcountyID    string
+		subcountyID    SubcountyID
 		expectedPath   string
 		response       string
 		expectedResult []Parish
@@ -0,0 +1,197 @@
+package opendataug
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// AddressCandidate is one interpretation of a free-text location
+type AddressCandidate struct {
+	// Path is the path down to the most specific unit matched
+	Path *AdminPath `json:"path"`
+	// Unit is the most specific unit matched
+	Unit AdminUnit `json:"unit"`
+	// Score is the share of the text explained by units on the path,
+	// weighted by how well each unit matched, from 0 to 1
+	Score float64 `json:"score"`
+	// Matched lists the units on the path that matched part of the text,
+	// from the top of the hierarchy down
+	Matched []AdminUnit `json:"matched"`
+}
+
+// AddressParser resolves messy free-text locations such as
+// "kawempe div, kampala" or "Nakawa/Kampala" against an AdminTree. It is safe
+// for concurrent use.
+type AddressParser struct {
+	tree     *AdminTree
+	index    map[string][]AdminUnit
+	phonetic map[string][]AdminUnit
+}
+
+// maxPhraseWords bounds the length of the phrases matched against names
+const maxPhraseWords = 5
+
+// phoneticMatchQuality is the weight of a phrase that only matches a name by
+// its PhoneticKey
+const phoneticMatchQuality = 0.9
+
+// NewAddressParser indexes the names of every unit linked into tree
+func NewAddressParser(tree *AdminTree) *AddressParser {
+	p := &AddressParser{
+		tree:     tree,
+		index:    make(map[string][]AdminUnit),
+		phonetic: make(map[string][]AdminUnit),
+	}
+
+	for u := range tree.DepthFirst(nil) {
+		key := DefaultNormalizer.Key(u.UnitName())
+		if key == "" {
+			continue
+		}
+		p.index[key] = append(p.index[key], u)
+
+		phonetic := PhoneticKey(u.UnitName())
+		p.phonetic[phonetic] = append(p.phonetic[phonetic], u)
+	}
+
+	return p
+}
+
+// phraseMatch records that the words [start, end) of the text matched unit
+type phraseMatch struct {
+	start, end int
+	unit       AdminUnit
+	quality    float64
+}
+
+// Parse returns the candidate paths for text, best first. Each unit matched
+// by part of the text yields a candidate, scored by how much of the text is
+// explained by units consistent with its path, so that "Nakawa, Kampala"
+// prefers the Nakawa inside Kampala over any other Nakawa.
+func (p *AddressParser) Parse(text string) []AddressCandidate {
+	segments := tokenizeAddress(text)
+
+	var (
+		words   int
+		matches []phraseMatch
+	)
+	for _, segment := range segments {
+		for start := range segment {
+			for end := start + 1; end <= len(segment) && end-start <= maxPhraseWords; end++ {
+				phrase := strings.Join(segment[start:end], " ")
+				for _, m := range p.lookup(phrase) {
+					m.start, m.end = words+start, words+end
+					matches = append(matches, m)
+				}
+			}
+		}
+		words += len(segment)
+	}
+
+	seen := make(map[UnitKey]bool)
+	var candidates []AddressCandidate
+	for _, m := range matches {
+		key := KeyOf(m.unit)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		path, ok := p.tree.Path(m.unit)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, scoreCandidate(path, m.unit, matches, words))
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if len(a.Matched) != len(b.Matched) {
+			return len(a.Matched) > len(b.Matched)
+		}
+		return a.Unit.Level() > b.Unit.Level()
+	})
+
+	return candidates
+}
+
+// lookup returns the units named by phrase. Phrases that name no unit exactly
+// fall back to units with the same PhoneticKey, at a lower quality.
+func (p *AddressParser) lookup(phrase string) []phraseMatch {
+	key := DefaultNormalizer.Key(phrase)
+	if key == "" {
+		return nil
+	}
+
+	quality := 1.0
+	found := p.index[key]
+	if len(found) == 0 {
+		quality = phoneticMatchQuality
+		found = p.phonetic[PhoneticKey(phrase)]
+	}
+
+	matches := make([]phraseMatch, len(found))
+	for i, u := range found {
+		matches[i] = phraseMatch{unit: u, quality: quality}
+	}
+	return matches
+}
+
+func scoreCandidate(path *AdminPath, unit AdminUnit, matches []phraseMatch, words int) AddressCandidate {
+	covered := make([]float64, words)
+	matched := make(map[Level]AdminUnit)
+
+	for _, m := range matches {
+		onPath, ok := path.Unit(m.unit.Level())
+		if !ok || onPath.UnitID() != m.unit.UnitID() {
+			continue
+		}
+		matched[m.unit.Level()] = onPath
+		for i := m.start; i < m.end; i++ {
+			if m.quality > covered[i] {
+				covered[i] = m.quality
+			}
+		}
+	}
+
+	candidate := AddressCandidate{Path: path, Unit: unit}
+	for _, level := range Levels {
+		if u, ok := matched[level]; ok {
+			candidate.Matched = append(candidate.Matched, u)
+		}
+	}
+
+	if words > 0 {
+		total := 0.0
+		for _, quality := range covered {
+			total += quality
+		}
+		candidate.Score = total / float64(words)
+	}
+
+	return candidate
+}
+
+// tokenizeAddress splits text into segments at separators and each segment
+// into lower-case words
+func tokenizeAddress(text string) [][]string {
+	text = punctuatedAbbreviations.ReplaceAllString(text, "$1$2")
+
+	var segments [][]string
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(",;/|\\\n", r)
+	}) {
+		words := strings.FieldsFunc(strings.ToLower(part), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+		})
+		if len(words) > 0 {
+			segments = append(segments, words)
+		}
+	}
+
+	return segments
+}
@@ -0,0 +1,103 @@
+package opendataug
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeAddress(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected [][]string
+	}{
+		{input: "kawempe div, kampala", expected: [][]string{{"kawempe", "div"}, {"kampala"}}},
+		{input: "Nakawa/Kampala", expected: [][]string{{"nakawa"}, {"kampala"}}},
+		{input: "Kyotera T/C; Rakai", expected: [][]string{{"kyotera", "tc"}, {"rakai"}}},
+		{input: " , ", expected: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			segments := tokenizeAddress(tc.input)
+			if !reflect.DeepEqual(segments, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, segments)
+			}
+		})
+	}
+}
+
+func TestAddressParserParse(t *testing.T) {
+	parser := NewAddressParser(NewAdminTree(testDataset()))
+
+	candidates := parser.Parse("kawempe div, kampala")
+	if len(candidates) == 0 {
+		t.Fatal("Expected candidates, got none")
+	}
+
+	best := candidates[0]
+	if best.Unit.UnitID() != "subcounty-2" {
+		t.Errorf("Expected subcounty-2, got %s", best.Unit.UnitID())
+	}
+	if best.Score != 1 {
+		t.Errorf("Expected score 1, got %v", best.Score)
+	}
+	if best.Path.District.ID != "district-1" {
+		t.Errorf("Expected district-1, got %s", best.Path.District.ID)
+	}
+
+	var matched []string
+	for _, u := range best.Matched {
+		matched = append(matched, u.UnitID())
+	}
+	if !reflect.DeepEqual(matched, []string{"district-1", "county-2", "subcounty-2"}) {
+		t.Errorf("Expected [district-1 county-2 subcounty-2], got %v", matched)
+	}
+
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Score > candidates[i-1].Score {
+			t.Errorf("Expected candidates to be ranked by score, got %v after %v", candidates[i].Score, candidates[i-1].Score)
+		}
+	}
+}
+
+func TestAddressParserDisambiguates(t *testing.T) {
+	ds := testDataset()
+	ds.Subcounties = append(ds.Subcounties, Subcounty{ID: "subcounty-4", Name: "Nakawa", CountyID: "county-3"})
+
+	parser := NewAddressParser(NewAdminTree(ds))
+
+	candidates := parser.Parse("Nakawa/Wakiso")
+	if len(candidates) == 0 {
+		t.Fatal("Expected candidates, got none")
+	}
+	if candidates[0].Unit.UnitID() != "subcounty-4" {
+		t.Errorf("Expected subcounty-4, got %s", candidates[0].Unit.UnitID())
+	}
+
+	candidates = parser.Parse("Nakawa/Kampala")
+	if len(candidates) == 0 {
+		t.Fatal("Expected candidates, got none")
+	}
+	if candidates[0].Path.District.ID != "district-1" {
+		t.Errorf("Expected a path in district-1, got %s", candidates[0].Path.District.ID)
+	}
+}
+
+func TestAddressParserPartialMatch(t *testing.T) {
+	parser := NewAddressParser(NewAdminTree(testDataset()))
+
+	candidates := parser.Parse("near the market, Ntinda")
+	if len(candidates) != 1 {
+		t.Fatalf("Expected 1 candidate, got %d", len(candidates))
+	}
+	if candidates[0].Unit.UnitID() != "parish-2" {
+		t.Errorf("Expected parish-2, got %s", candidates[0].Unit.UnitID())
+	}
+	if candidates[0].Score != 0.25 {
+		t.Errorf("Expected score 0.25, got %v", candidates[0].Score)
+	}
+
+	if candidates := parser.Parse("nowhere"); len(candidates) != 0 {
+		t.Errorf("Expected no candidates, got %+v", candidates)
+	}
+}
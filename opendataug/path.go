@@ -0,0 +1,287 @@
+package opendataug
+
+import (
+	"context"
+	"fmt"
+)
+
+// Region identifies the region a district belongs to
+type Region struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AdminPath is the chain of units from a region down to a specific unit.
+// Levels below the unit the path was resolved for are nil.
+type AdminPath struct {
+	Region    Region     `json:"region"`
+	District  *District  `json:"district,omitempty"`
+	County    *County    `json:"county,omitempty"`
+	Subcounty *Subcounty `json:"subcounty,omitempty"`
+	Parish    *Parish    `json:"parish,omitempty"`
+	Village   *Village   `json:"village,omitempty"`
+}
+
+// PathResolver resolves the ancestry of units through an API. Ancestors are
+// cached, so resolving many units in the same area costs one request per
+// unit plus one per distinct ancestor. A PathResolver is safe for concurrent
+// use.
+type PathResolver struct {
+	api   API
+	limit int
+
+	districts   memo[DistrictID, *District]
+	counties    memo[CountyID, *County]
+	subcounties memo[SubcountyID, *Subcounty]
+	parishes    memo[ParishID, *Parish]
+}
+
+// NewPathResolver returns a PathResolver using api. Batches are resolved with
+// the concurrency limit of api when it is a Client.
+func NewPathResolver(api API) *PathResolver {
+	return &PathResolver{api: api, limit: concurrencyLimit(api)}
+}
+
+// ClearCache forgets every cached ancestor
+func (r *PathResolver) ClearCache() {
+	r.districts.reset()
+	r.counties.reset()
+	r.subcounties.reset()
+	r.parishes.reset()
+}
+
+// ResolveDistrictPath returns the path from the region down to a specific district
+func (r *PathResolver) ResolveDistrictPath(ctx context.Context, id DistrictID) (*AdminPath, error) {
+	district, err := r.lookupDistrict(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.districtPath(ctx, district)
+}
+
+// ResolveCountyPath returns the path from the region down to a specific county
+func (r *PathResolver) ResolveCountyPath(ctx context.Context, id CountyID) (*AdminPath, error) {
+	county, err := r.lookupCounty(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.countyPath(ctx, county)
+}
+
+// ResolveSubcountyPath returns the path from the region down to a specific subcounty
+func (r *PathResolver) ResolveSubcountyPath(ctx context.Context, id SubcountyID) (*AdminPath, error) {
+	subcounty, err := r.lookupSubcounty(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.subcountyPath(ctx, subcounty)
+}
+
+// ResolveParishPath returns the path from the region down to a specific parish
+func (r *PathResolver) ResolveParishPath(ctx context.Context, id ParishID) (*AdminPath, error) {
+	parish, err := r.lookupParish(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.parishPath(ctx, parish)
+}
+
+// ResolveVillagePath returns the path from the region down to a specific village
+func (r *PathResolver) ResolveVillagePath(ctx context.Context, id VillageID) (*AdminPath, error) {
+	village, err := r.lookupVillage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.villagePath(ctx, village)
+}
+
+// ResolveDistrictPaths resolves the paths of many districts concurrently. IDs
+// that do not exist are left out of the result; any other error aborts the
+// whole batch.
+func (r *PathResolver) ResolveDistrictPaths(ctx context.Context, ids []DistrictID) (map[DistrictID]*AdminPath, error) {
+	return resolvePaths(ctx, r.limit, ids, r.lookupDistrict, r.districtPath)
+}
+
+// ResolveCountyPaths resolves the paths of many counties concurrently. IDs
+// that do not exist are left out of the result; any other error, including
+// a missing ancestor of a county that does exist, aborts the whole batch.
+func (r *PathResolver) ResolveCountyPaths(ctx context.Context, ids []CountyID) (map[CountyID]*AdminPath, error) {
+	return resolvePaths(ctx, r.limit, ids, r.lookupCounty, r.countyPath)
+}
+
+// ResolveSubcountyPaths resolves the paths of many subcounties concurrently.
+// IDs that do not exist are left out of the result; any other error,
+// including a missing ancestor of a subcounty that does exist, aborts the
+// whole batch.
+func (r *PathResolver) ResolveSubcountyPaths(ctx context.Context, ids []SubcountyID) (map[SubcountyID]*AdminPath, error) {
+	return resolvePaths(ctx, r.limit, ids, r.lookupSubcounty, r.subcountyPath)
+}
+
+// ResolveVillagePaths resolves the paths of many villages concurrently. IDs
+// that do not exist are left out of the result; any other error, including
+// a missing ancestor of a village that does exist, aborts the whole batch.
+func (r *PathResolver) ResolveVillagePaths(ctx context.Context, ids []VillageID) (map[VillageID]*AdminPath, error) {
+	return resolvePaths(ctx, r.limit, ids, r.lookupVillage, r.villagePath)
+}
+
+// ResolveParishPaths resolves the paths of many parishes concurrently. IDs
+// that do not exist are left out of the result; any other error, including
+// a missing ancestor of a parish that does exist, aborts the whole batch.
+func (r *PathResolver) ResolveParishPaths(ctx context.Context, ids []ParishID) (map[ParishID]*AdminPath, error) {
+	return resolvePaths(ctx, r.limit, ids, r.lookupParish, r.parishPath)
+}
+
+func (r *PathResolver) lookupDistrict(ctx context.Context, id DistrictID) (*District, error) {
+	district, err := r.districts.get(ctx, id, r.api.GetDistrictContext)
+	if err != nil {
+		return nil, fmt.Errorf("resolving district %s: %w", id, err)
+	}
+	return district, nil
+}
+
+func (r *PathResolver) lookupCounty(ctx context.Context, id CountyID) (*County, error) {
+	county, err := r.counties.get(ctx, id, r.api.GetCountyContext)
+	if err != nil {
+		return nil, fmt.Errorf("resolving county %s: %w", id, err)
+	}
+	return county, nil
+}
+
+func (r *PathResolver) lookupSubcounty(ctx context.Context, id SubcountyID) (*Subcounty, error) {
+	subcounty, err := r.subcounties.get(ctx, id, r.api.GetSubcountyContext)
+	if err != nil {
+		return nil, fmt.Errorf("resolving subcounty %s: %w", id, err)
+	}
+	return subcounty, nil
+}
+
+func (r *PathResolver) lookupParish(ctx context.Context, id ParishID) (*Parish, error) {
+	parish, err := r.parishes.get(ctx, id, r.api.GetParishContext)
+	if err != nil {
+		return nil, fmt.Errorf("resolving parish %s: %w", id, err)
+	}
+	return parish, nil
+}
+
+func (r *PathResolver) lookupVillage(ctx context.Context, id VillageID) (*Village, error) {
+	village, err := r.api.GetVillageContext(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("resolving village %s: %w", id, err)
+	}
+	return village, nil
+}
+
+// districtPath starts a path at a district that has already been looked up
+func (r *PathResolver) districtPath(ctx context.Context, district *District) (*AdminPath, error) {
+	d := *district
+	return &AdminPath{
+		Region:   Region{ID: d.RegionID, Name: d.RegionName},
+		District: &d,
+	}, nil
+}
+
+// countyPath resolves the ancestors of a county that has already been looked
+// up and appends a copy of it. The levels below follow the same pattern.
+func (r *PathResolver) countyPath(ctx context.Context, county *County) (*AdminPath, error) {
+	path, err := r.ResolveDistrictPath(ctx, county.DistrictID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := *county
+	path.County = &c
+	return path, nil
+}
+
+func (r *PathResolver) subcountyPath(ctx context.Context, subcounty *Subcounty) (*AdminPath, error) {
+	path, err := r.ResolveCountyPath(ctx, subcounty.CountyID)
+	if err != nil {
+		return nil, err
+	}
+
+	s := *subcounty
+	path.Subcounty = &s
+	return path, nil
+}
+
+func (r *PathResolver) parishPath(ctx context.Context, parish *Parish) (*AdminPath, error) {
+	path, err := r.ResolveSubcountyPath(ctx, parish.SubcountyID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := *parish
+	path.Parish = &p
+	return path, nil
+}
+
+func (r *PathResolver) villagePath(ctx context.Context, village *Village) (*AdminPath, error) {
+	path, err := r.ResolveParishPath(ctx, village.ParishID)
+	if err != nil {
+		return nil, err
+	}
+
+	path.Village = village
+	return path, nil
+}
+
+// resolvePaths looks up each ID and then resolves its ancestors. Only a not
+// found from the lookup of the ID itself drops it from the result.
+func resolvePaths[K comparable, T any](ctx context.Context, limit int, ids []K, lookup func(context.Context, K) (T, error), extend func(context.Context, T) (*AdminPath, error)) (map[K]*AdminPath, error) {
+	type resolved struct {
+		id   K
+		path *AdminPath
+	}
+
+	results, err := fanOut(ctx, limit, ids, func(ctx context.Context, id K) ([]resolved, error) {
+		unit, err := lookup(ctx, id)
+		if isNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := extend(ctx, unit)
+		if err != nil {
+			return nil, err
+		}
+		return []resolved{{id: id, path: path}}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[K]*AdminPath, len(results))
+	for _, result := range results {
+		paths[result.id] = result.path
+	}
+
+	return paths, nil
+}
+
+// Unit returns the unit of the path at level, if the path reaches it
+func (p *AdminPath) Unit(level Level) (AdminUnit, bool) {
+	switch {
+	case level == LevelDistrict && p.District != nil:
+		return *p.District, true
+	case level == LevelCounty && p.County != nil:
+		return *p.County, true
+	case level == LevelSubcounty && p.Subcounty != nil:
+		return *p.Subcounty, true
+	case level == LevelParish && p.Parish != nil:
+		return *p.Parish, true
+	case level == LevelVillage && p.Village != nil:
+		return *p.Village, true
+	}
+	return nil, false
+}
+
+// Name returns the name of the unit of the path at level, or an empty string
+// if the path does not reach it
+func (p *AdminPath) Name(level Level) string {
+	if u, ok := p.Unit(level); ok {
+		return u.UnitName()
+	}
+	return ""
+}
@@ -0,0 +1,266 @@
+package opendataug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// unitServer serves every unit of ds from its single-unit endpoint and counts
+// the requests made for each path
+func unitServer(t *testing.T, ds *Dataset) (*httptest.Server, *Client, func(string) int) {
+	routes := make(map[string]string)
+	add := func(path string, data interface{}) {
+		body, err := json.Marshal(map[string]interface{}{"data": data})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		routes[path] = string(body)
+	}
+
+	for _, d := range ds.Districts {
+		add(fmt.Sprintf("/districts/%s", d.ID), d)
+	}
+	for _, c := range ds.Counties {
+		add(fmt.Sprintf("/counties/%s", c.ID), c)
+	}
+	for _, s := range ds.Subcounties {
+		add(fmt.Sprintf("/subcounties/%s", s.ID), s)
+	}
+	for _, p := range ds.Parishes {
+		add(fmt.Sprintf("/parishes/%s", p.ID), p)
+	}
+	for _, v := range ds.Villages {
+		add(fmt.Sprintf("/villages/%s", v.ID), v)
+	}
+
+	var mu sync.Mutex
+	hits := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+
+		response, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "Not found"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(response))
+	}))
+
+	baseURL = server.URL
+
+	count := func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits[path]
+	}
+
+	return server, NewClient("test-api-key"), count
+}
+
+func TestResolveVillagePath(t *testing.T) {
+	server, client, _ := unitServer(t, testDataset())
+	defer server.Close()
+
+	resolver := NewPathResolver(client)
+
+	path, err := resolver.ResolveVillagePath(context.Background(), "village-3")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if path.Region != (Region{ID: "region-1", Name: "Central"}) {
+		t.Errorf("Expected region Central, got %+v", path.Region)
+	}
+	if path.District.ID != "district-1" {
+		t.Errorf("Expected district-1, got %s", path.District.ID)
+	}
+	if path.County.ID != "county-1" {
+		t.Errorf("Expected county-1, got %s", path.County.ID)
+	}
+	if path.Subcounty.ID != "subcounty-1" {
+		t.Errorf("Expected subcounty-1, got %s", path.Subcounty.ID)
+	}
+	if path.Parish.ID != "parish-2" {
+		t.Errorf("Expected parish-2, got %s", path.Parish.ID)
+	}
+	if path.Village.ID != "village-3" {
+		t.Errorf("Expected village-3, got %s", path.Village.ID)
+	}
+}
+
+func TestResolveCountyPath(t *testing.T) {
+	server, client, _ := unitServer(t, testDataset())
+	defer server.Close()
+
+	path, err := NewPathResolver(client).ResolveCountyPath(context.Background(), "county-3")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if path.District.Name != "Wakiso" || path.County.Name != "Kyadondo" {
+		t.Errorf("Expected Wakiso/Kyadondo, got %+v", path)
+	}
+	if path.Subcounty != nil || path.Parish != nil || path.Village != nil {
+		t.Errorf("Expected levels below the county to be nil, got %+v", path)
+	}
+}
+
+func TestResolveVillagePathMissingParent(t *testing.T) {
+	server, client, _ := unitServer(t, testDataset())
+	defer server.Close()
+
+	_, err := NewPathResolver(client).ResolveVillagePath(context.Background(), "village-9")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a not found APIError, got %v", err)
+	}
+}
+
+func TestResolveVillagePaths(t *testing.T) {
+	server, client, hits := unitServer(t, testDataset())
+	defer server.Close()
+
+	resolver := NewPathResolver(client)
+
+	ids := []VillageID{"village-1", "village-2", "village-3", "village-4", "village-missing"}
+	paths, err := resolver.ResolveVillagePaths(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(paths) != 4 {
+		t.Errorf("Expected 4 paths, got %d", len(paths))
+	}
+	if _, ok := paths["village-missing"]; ok {
+		t.Error("Expected missing village to be left out")
+	}
+	if paths["village-4"].Parish.ID != "parish-3" {
+		t.Errorf("Expected parish-3, got %s", paths["village-4"].Parish.ID)
+	}
+
+	if n := hits("/parishes/parish-1"); n != 1 {
+		t.Errorf("Expected parish-1 to be fetched once, got %d", n)
+	}
+	if n := hits("/districts/district-1"); n != 1 {
+		t.Errorf("Expected district-1 to be fetched once, got %d", n)
+	}
+
+	paths["village-1"].District.Name = "Changed"
+	path, err := resolver.ResolveParishPath(context.Background(), "parish-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if path.District.Name != "Kampala" {
+		t.Errorf("Expected cached district to be unaffected, got %s", path.District.Name)
+	}
+
+	resolver.ClearCache()
+	if _, err := resolver.ResolveParishPath(context.Background(), "parish-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := hits("/parishes/parish-1"); n != 2 {
+		t.Errorf("Expected parish-1 to be fetched again after clearing the cache, got %d", n)
+	}
+}
+
+func TestResolveVillagePathsMissingParent(t *testing.T) {
+	server, client, _ := unitServer(t, testDataset())
+	defer server.Close()
+
+	ids := []VillageID{"village-1", "village-missing", "village-9"}
+	paths, err := NewPathResolver(client).ResolveVillagePaths(context.Background(), ids)
+
+	// village-9 exists, so its missing parish is an error rather than a gap
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a not found APIError, got %v", err)
+	}
+	if paths != nil {
+		t.Errorf("Expected no paths, got %+v", paths)
+	}
+}
+
+func TestResolvePathsAllLevels(t *testing.T) {
+	server, client, _ := unitServer(t, testDataset())
+	defer server.Close()
+
+	ctx := context.Background()
+	resolver := NewPathResolver(client)
+
+	tests := []struct {
+		name    string
+		resolve func() (map[string]*AdminPath, error)
+		level   Level
+		id      string
+	}{
+		{
+			name: "Districts",
+			resolve: func() (map[string]*AdminPath, error) {
+				paths, err := resolver.ResolveDistrictPaths(ctx, []DistrictID{"district-2", "district-missing"})
+				return stringKeys(paths), err
+			},
+			level: LevelDistrict,
+			id:    "district-2",
+		},
+		{
+			name: "Counties",
+			resolve: func() (map[string]*AdminPath, error) {
+				paths, err := resolver.ResolveCountyPaths(ctx, []CountyID{"county-3", "county-missing"})
+				return stringKeys(paths), err
+			},
+			level: LevelCounty,
+			id:    "county-3",
+		},
+		{
+			name: "Subcounties",
+			resolve: func() (map[string]*AdminPath, error) {
+				paths, err := resolver.ResolveSubcountyPaths(ctx, []SubcountyID{"subcounty-3", "subcounty-missing"})
+				return stringKeys(paths), err
+			},
+			level: LevelSubcounty,
+			id:    "subcounty-3",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			paths, err := tc.resolve()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(paths) != 1 {
+				t.Fatalf("Expected 1 path, got %+v", paths)
+			}
+
+			path := paths[tc.id]
+			if u, ok := path.Unit(tc.level); !ok || u.UnitID() != tc.id {
+				t.Errorf("Expected the path to end at %s, got %+v", tc.id, path)
+			}
+			if path.District.Name != "Wakiso" || path.Region.Name != "Central" {
+				t.Errorf("Expected a path through Wakiso in Central, got %+v", path)
+			}
+		})
+	}
+}
+
+func stringKeys[K ~string](paths map[K]*AdminPath) map[string]*AdminPath {
+	out := make(map[string]*AdminPath, len(paths))
+	for id, path := range paths {
+		out[string(id)] = path
+	}
+	return out
+}
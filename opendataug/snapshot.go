@@ -0,0 +1,352 @@
+package opendataug
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot layout written by
+// WriteSnapshot
+const SnapshotVersion = 1
+
+// SnapshotManifestFile is the name of the manifest inside a snapshot
+// directory
+const SnapshotManifestFile = "manifest.json"
+
+// snapshotFiles names the NDJSON file holding each level of a snapshot
+var snapshotFiles = map[Level]string{
+	LevelDistrict:  "districts.ndjson",
+	LevelCounty:    "counties.ndjson",
+	LevelSubcounty: "subcounties.ndjson",
+	LevelParish:    "parishes.ndjson",
+	LevelVillage:   "villages.ndjson",
+}
+
+// ErrSnapshotChecksum is returned when a snapshot file does not match the
+// checksum recorded in its manifest
+var ErrSnapshotChecksum = errors.New("opendataug: snapshot checksum mismatch")
+
+// ErrSnapshotIncomplete is returned when reading a snapshot whose export has
+// not finished
+var ErrSnapshotIncomplete = errors.New("opendataug: snapshot is incomplete")
+
+// SnapshotFile describes the file holding one level of a snapshot
+type SnapshotFile struct {
+	Level     Level     `json:"level"`
+	Path      string    `json:"path"`
+	Count     int       `json:"count"`
+	SHA256    string    `json:"sha256"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// SnapshotManifest describes the contents of a snapshot directory
+type SnapshotManifest struct {
+	Version    int    `json:"version"`
+	APIVersion string `json:"api_version"`
+	// FetchedAt is when the export started. Each file records when its own
+	// level was fetched.
+	FetchedAt time.Time      `json:"fetched_at"`
+	Complete  bool           `json:"complete"`
+	Counts    Counts         `json:"counts"`
+	Files     []SnapshotFile `json:"files"`
+}
+
+// WriteSnapshot crawls every level of the hierarchy and writes it to dir as
+// one NDJSON file per level plus a manifest. The manifest is rewritten after
+// each level, so an interrupted export can be resumed by calling
+// WriteSnapshot again with the same dir: levels whose files still match
+// their recorded checksums are not fetched again. Once a snapshot is
+// complete, writing to the same dir refreshes every level.
+func WriteSnapshot(ctx context.Context, api API, dir string) (*SnapshotManifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	m := &SnapshotManifest{
+		Version:    SnapshotVersion,
+		APIVersion: apiVersion(),
+		FetchedAt:  time.Now().UTC(),
+	}
+
+	done := make(map[Level]SnapshotFile)
+	if prev, err := ReadSnapshotManifest(dir); err == nil && !prev.Complete &&
+		prev.Version == m.Version && prev.APIVersion == m.APIVersion {
+		m.FetchedAt = prev.FetchedAt
+		for _, f := range prev.Files {
+			if verifySnapshotFile(dir, f) == nil {
+				done[f.Level] = f
+			}
+		}
+	}
+
+	levels := []struct {
+		level Level
+		write func(ctx context.Context) (SnapshotFile, error)
+	}{
+		{LevelDistrict, func(ctx context.Context) (SnapshotFile, error) {
+			return snapshotLevel(ctx, dir, LevelDistrict, api.GetDistrictsContext)
+		}},
+		{LevelCounty, func(ctx context.Context) (SnapshotFile, error) {
+			return snapshotLevel(ctx, dir, LevelCounty, api.GetCountiesContext)
+		}},
+		{LevelSubcounty, func(ctx context.Context) (SnapshotFile, error) {
+			return snapshotLevel(ctx, dir, LevelSubcounty, api.GetSubcountiesContext)
+		}},
+		{LevelParish, func(ctx context.Context) (SnapshotFile, error) {
+			return snapshotLevel(ctx, dir, LevelParish, api.GetParishesContext)
+		}},
+		{LevelVillage, func(ctx context.Context) (SnapshotFile, error) {
+			return snapshotLevel(ctx, dir, LevelVillage, api.GetVillagesContext)
+		}},
+	}
+
+	for _, l := range levels {
+		if _, ok := done[l.level]; ok {
+			continue
+		}
+
+		f, err := l.write(ctx)
+		if err != nil {
+			return nil, err
+		}
+		done[l.level] = f
+
+		m.Files = orderedSnapshotFiles(done)
+		if err := writeSnapshotManifest(dir, m); err != nil {
+			return nil, err
+		}
+	}
+
+	m.Files = orderedSnapshotFiles(done)
+	for _, f := range m.Files {
+		m.Counts = m.Counts.add(countsAt(f.Level, f.Count))
+	}
+	m.Complete = true
+	if err := writeSnapshotManifest(dir, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// ReadSnapshotManifest reads the manifest of the snapshot in dir
+func ReadSnapshotManifest(dir string) (*SnapshotManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, SnapshotManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var m SnapshotManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("opendataug: reading snapshot manifest: %w", err)
+	}
+	if m.Version != SnapshotVersion {
+		return nil, fmt.Errorf("opendataug: unsupported snapshot version %d", m.Version)
+	}
+
+	return &m, nil
+}
+
+// ReadSnapshot reads the snapshot in dir, verifying every file against the
+// checksums in its manifest
+func ReadSnapshot(dir string) (*Dataset, *SnapshotManifest, error) {
+	m, err := ReadSnapshotManifest(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !m.Complete {
+		return nil, nil, ErrSnapshotIncomplete
+	}
+
+	ds := &Dataset{}
+	for _, f := range m.Files {
+		switch f.Level {
+		case LevelDistrict:
+			ds.Districts, err = readSnapshotLevel[District](dir, f)
+		case LevelCounty:
+			ds.Counties, err = readSnapshotLevel[County](dir, f)
+		case LevelSubcounty:
+			ds.Subcounties, err = readSnapshotLevel[Subcounty](dir, f)
+		case LevelParish:
+			ds.Parishes, err = readSnapshotLevel[Parish](dir, f)
+		case LevelVillage:
+			ds.Villages, err = readSnapshotLevel[Village](dir, f)
+		default:
+			err = fmt.Errorf("opendataug: snapshot file %q has invalid level %d", f.Path, int(f.Level))
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return ds, m, nil
+}
+
+// snapshotLevel fetches one level and writes it to its NDJSON file. The file
+// is written under a temporary name and renamed once complete, so an
+// interrupted write never leaves a truncated file behind.
+func snapshotLevel[T any](ctx context.Context, dir string, level Level, fetch func(context.Context) ([]T, error)) (SnapshotFile, error) {
+	fetchedAt := time.Now().UTC()
+	items, err := fetch(ctx)
+	if err != nil {
+		return SnapshotFile{}, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return SnapshotFile{}, err
+		}
+	}
+
+	name := snapshotFiles[level]
+	if err := writeFileAtomic(filepath.Join(dir, name), buf.Bytes()); err != nil {
+		return SnapshotFile{}, err
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return SnapshotFile{
+		Level:     level,
+		Path:      name,
+		Count:     len(items),
+		SHA256:    hex.EncodeToString(sum[:]),
+		FetchedAt: fetchedAt,
+	}, nil
+}
+
+func readSnapshotLevel[T any](dir string, f SnapshotFile) ([]T, error) {
+	data, err := readSnapshotFile(dir, f)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, f.Count)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var item T
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			return nil, fmt.Errorf("opendataug: reading %s: %w", f.Path, err)
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(items) != f.Count {
+		return nil, fmt.Errorf("opendataug: %s has %d records, manifest says %d", f.Path, len(items), f.Count)
+	}
+
+	return items, nil
+}
+
+// readSnapshotFile reads a snapshot file and checks it against its checksum
+func readSnapshotFile(dir string, f SnapshotFile) ([]byte, error) {
+	if f.Path != path.Base(f.Path) {
+		return nil, fmt.Errorf("opendataug: snapshot file %q is outside the snapshot", f.Path)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, f.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != f.SHA256 {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotChecksum, f.Path)
+	}
+
+	return data, nil
+}
+
+func verifySnapshotFile(dir string, f SnapshotFile) error {
+	_, err := readSnapshotFile(dir, f)
+	return err
+}
+
+func writeSnapshotManifest(dir string, m *SnapshotManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, SnapshotManifestFile), append(data, '\n'))
+}
+
+// writeFileAtomic writes data to a temporary file next to name and renames it
+// into place
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, bytes.NewReader(data)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+// orderedSnapshotFiles returns the files in done from the top level down
+func orderedSnapshotFiles(done map[Level]SnapshotFile) []SnapshotFile {
+	files := make([]SnapshotFile, 0, len(done))
+	for _, level := range Levels {
+		if f, ok := done[level]; ok {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// countsAt returns Counts with n units at a specific level
+func countsAt(level Level, n int) Counts {
+	var c Counts
+	switch level {
+	case LevelDistrict:
+		c.Districts = n
+	case LevelCounty:
+		c.Counties = n
+	case LevelSubcounty:
+		c.Subcounties = n
+	case LevelParish:
+		c.Parishes = n
+	case LevelVillage:
+		c.Villages = n
+	}
+	return c
+}
+
+// apiVersion returns the version segment of the API's base URL, such as "v1"
+func apiVersion() string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Path == "" || u.Path == "/" {
+		return ""
+	}
+	return path.Base(u.Path)
+}
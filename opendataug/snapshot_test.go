@@ -0,0 +1,171 @@
+package opendataug
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	ds := testDataset()
+	server, client := TestRoutesServer(t, datasetRoutes(t, ds))
+	defer server.Close()
+
+	dir := t.TempDir()
+	m, err := WriteSnapshot(context.Background(), client, dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedCounts := Counts{Districts: 2, Counties: 3, Subcounties: 3, Parishes: 4, Villages: 6}
+	if !m.Complete || m.Counts != expectedCounts || len(m.Files) != 5 {
+		t.Errorf("Expected a complete manifest with counts %+v, got %+v", expectedCounts, m)
+	}
+	if m.Version != SnapshotVersion || m.FetchedAt.IsZero() {
+		t.Errorf("Expected version %d and a fetch time, got %+v", SnapshotVersion, m)
+	}
+	for _, f := range m.Files {
+		if len(f.SHA256) != 64 {
+			t.Errorf("Expected a SHA-256 checksum for %s, got %q", f.Path, f.SHA256)
+		}
+	}
+
+	got, manifest, err := ReadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got, ds) {
+		t.Errorf("Expected %+v, got %+v", ds, got)
+	}
+	if !reflect.DeepEqual(manifest.Files, m.Files) {
+		t.Errorf("Expected files %+v, got %+v", m.Files, manifest.Files)
+	}
+}
+
+func TestWriteSnapshotResume(t *testing.T) {
+	ds := testDataset()
+	routes := datasetRoutes(t, ds)
+	villages := routes["/villages"]
+	delete(routes, "/villages")
+
+	server, client := TestRoutesServer(t, routes)
+	dir := t.TempDir()
+	_, err := WriteSnapshot(context.Background(), client, dir)
+	server.Close()
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+
+	if _, _, err := ReadSnapshot(dir); !errors.Is(err, ErrSnapshotIncomplete) {
+		t.Errorf("Expected ErrSnapshotIncomplete, got %v", err)
+	}
+
+	// Only the missing level is served, so resuming must not refetch the
+	// levels that were already written
+	server, client = TestRoutesServer(t, map[string]string{"/villages": villages})
+	defer server.Close()
+
+	if _, err := WriteSnapshot(context.Background(), client, dir); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got, _, err := ReadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got, ds) {
+		t.Errorf("Expected %+v, got %+v", ds, got)
+	}
+}
+
+func TestReadSnapshotChecksum(t *testing.T) {
+	ds := testDataset()
+	server, client := TestRoutesServer(t, datasetRoutes(t, ds))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if _, err := WriteSnapshot(context.Background(), client, dir); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	name := filepath.Join(dir, snapshotFiles[LevelParish])
+	if err := os.WriteFile(name, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := ReadSnapshot(dir); !errors.Is(err, ErrSnapshotChecksum) {
+		t.Errorf("Expected ErrSnapshotChecksum, got %v", err)
+	}
+
+	// A corrupted level is fetched again when the export is repeated
+	if _, err := WriteSnapshot(context.Background(), client, dir); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got, _, err := ReadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got.Parishes, ds.Parishes) {
+		t.Errorf("Expected %+v, got %+v", ds.Parishes, got.Parishes)
+	}
+}
+
+func TestAPIVersion(t *testing.T) {
+	defer func(u string) { baseURL = u }(baseURL)
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "https://api.opendataug.com/v1", expected: "v1"},
+		{url: "https://api.opendataug.com/v2/", expected: "v2"},
+		{url: "http://127.0.0.1:8080", expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			baseURL = tc.url
+			if got := apiVersion(); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestWriteSnapshotRefresh(t *testing.T) {
+	ds := testDataset()
+	server, client := TestRoutesServer(t, datasetRoutes(t, ds))
+	dir := t.TempDir()
+	first, err := WriteSnapshot(context.Background(), client, dir)
+	server.Close()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	changed := testDataset()
+	changed.Districts[0].Name = "Kampala Capital City"
+	server, client = TestRoutesServer(t, datasetRoutes(t, changed))
+	defer server.Close()
+
+	second, err := WriteSnapshot(context.Background(), client, dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !second.FetchedAt.After(first.FetchedAt) {
+		t.Errorf("Expected the fetch time to move past %v, got %v", first.FetchedAt, second.FetchedAt)
+	}
+
+	got, manifest, err := ReadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got, changed) {
+		t.Errorf("Expected %+v, got %+v", changed, got)
+	}
+	if !manifest.FetchedAt.Equal(second.FetchedAt) {
+		t.Errorf("Expected manifest fetch time %v, got %v", second.FetchedAt, manifest.FetchedAt)
+	}
+}
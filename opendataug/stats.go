@@ -0,0 +1,250 @@
+package opendataug
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// FanOut summarises how many children the units of one level have
+type FanOut struct {
+	// Level is the level of the parents
+	Level   Level   `json:"level"`
+	Parents int     `json:"parents"`
+	Min     int     `json:"min"`
+	Max     int     `json:"max"`
+	Median  float64 `json:"median"`
+}
+
+// RegionStats holds the number of units in one region
+type RegionStats struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Counts Counts `json:"counts"`
+}
+
+// DistrictStats holds the number of units below one district
+type DistrictStats struct {
+	ID         DistrictID `json:"id"`
+	Name       string     `json:"name"`
+	RegionName string     `json:"region_name"`
+	Counts     Counts     `json:"counts"`
+	// Missing lists the levels below the district that have no units
+	Missing []Level `json:"missing,omitempty"`
+}
+
+// Statistics describes the size and shape of the hierarchy
+type Statistics struct {
+	Totals    Counts          `json:"totals"`
+	FanOut    []FanOut        `json:"fan_out"`
+	Regions   []RegionStats   `json:"regions"`
+	Districts []DistrictStats `json:"districts"`
+}
+
+// ComputeStatistics loads every level of the hierarchy and computes its
+// statistics
+func ComputeStatistics(ctx context.Context, api API) (*Statistics, error) {
+	tree, err := BuildTree(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStatistics(tree), nil
+}
+
+// NewStatistics computes statistics for tree. Regions are ordered by name and
+// districts keep the order of the tree. Units that cannot be traced up to a
+// district only count towards the totals.
+func NewStatistics(tree *AdminTree) *Statistics {
+	s := &Statistics{
+		Totals:    tree.Counts(),
+		Regions:   []RegionStats{},
+		Districts: []DistrictStats{},
+	}
+
+	regions := make(map[string]*RegionStats)
+	for _, d := range tree.Districts() {
+		counts := tree.DistrictCounts(d.ID)
+
+		ds := DistrictStats{
+			ID:         d.ID,
+			Name:       d.Name,
+			RegionName: d.RegionName,
+			Counts:     counts,
+		}
+		for _, level := range Levels[1:] {
+			if counts.of(level) == 0 {
+				ds.Missing = append(ds.Missing, level)
+			}
+		}
+		s.Districts = append(s.Districts, ds)
+
+		r, ok := regions[d.RegionID]
+		if !ok {
+			r = &RegionStats{ID: d.RegionID, Name: d.RegionName}
+			regions[d.RegionID] = r
+		}
+		r.Counts = r.Counts.add(counts).add(Counts{Districts: 1})
+	}
+
+	for _, r := range regions {
+		s.Regions = append(s.Regions, *r)
+	}
+	sort.Slice(s.Regions, func(i, j int) bool {
+		if s.Regions[i].Name != s.Regions[j].Name {
+			return s.Regions[i].Name < s.Regions[j].Name
+		}
+		return s.Regions[i].ID < s.Regions[j].ID
+	})
+
+	s.FanOut = []FanOut{
+		fanOutOf(LevelDistrict, tree.districts, func(d District) int { return len(tree.countiesOf[d.ID]) }),
+		fanOutOf(LevelCounty, tree.counties, func(c County) int { return len(tree.subcountiesOf[c.ID]) }),
+		fanOutOf(LevelSubcounty, tree.subcounties, func(sc Subcounty) int { return len(tree.parishesOf[sc.ID]) }),
+		fanOutOf(LevelParish, tree.parishes, func(p Parish) int { return len(tree.villagesOf[p.ID]) }),
+	}
+
+	return s
+}
+
+// Incomplete returns the districts with at least one level below them that
+// has no units
+func (s *Statistics) Incomplete() []DistrictStats {
+	var incomplete []DistrictStats
+	for _, d := range s.Districts {
+		if len(d.Missing) > 0 {
+			incomplete = append(incomplete, d)
+		}
+	}
+	return incomplete
+}
+
+// WriteJSON writes the statistics to w as indented JSON
+func (s *Statistics) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteCSV writes one row for the totals, one per region and one per district
+// to w. The scope column tells the kinds of row apart, and the region column
+// is only filled for districts.
+func (s *Statistics) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	row := func(scope, id, name, region string, c Counts, missing []Level) []string {
+		names := make([]string, len(missing))
+		for i, level := range missing {
+			names[i] = level.String()
+		}
+		return []string{
+			scope, id, name, region,
+			strconv.Itoa(c.Districts),
+			strconv.Itoa(c.Counties),
+			strconv.Itoa(c.Subcounties),
+			strconv.Itoa(c.Parishes),
+			strconv.Itoa(c.Villages),
+			strings.Join(names, ";"),
+		}
+	}
+
+	records := [][]string{
+		{"scope", "id", "name", "region", "districts", "counties", "subcounties", "parishes", "villages", "missing"},
+		row("total", "", "", "", s.Totals, nil),
+	}
+	for _, r := range s.Regions {
+		records = append(records, row("region", r.ID, r.Name, "", r.Counts, nil))
+	}
+	for _, d := range s.Districts {
+		records = append(records, row("district", string(d.ID), d.Name, d.RegionName, d.Counts, d.Missing))
+	}
+
+	return cw.WriteAll(records)
+}
+
+// Table returns the statistics as aligned plain-text tables
+func (s *Statistics) Table() string {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	counts := func(c Counts) string {
+		return fmt.Sprintf("%d\t%d\t%d\t%d", c.Counties, c.Subcounties, c.Parishes, c.Villages)
+	}
+
+	fmt.Fprintln(tw, "LEVEL\tCOUNT")
+	for _, level := range Levels {
+		fmt.Fprintf(tw, "%s\t%d\n", level, s.Totals.of(level))
+	}
+
+	fmt.Fprintln(tw, "\nFAN-OUT\tPARENTS\tMIN\tMAX\tMEDIAN")
+	for _, f := range s.FanOut {
+		fmt.Fprintf(tw, "%s -> %s\t%d\t%d\t%d\t%g\n", f.Level, f.Level.Child(), f.Parents, f.Min, f.Max, f.Median)
+	}
+
+	fmt.Fprintln(tw, "\nREGION\tDISTRICTS\tCOUNTIES\tSUBCOUNTIES\tPARISHES\tVILLAGES")
+	for _, r := range s.Regions {
+		name := r.Name
+		if name == "" {
+			name = "(none)"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", name, r.Counts.Districts, counts(r.Counts))
+	}
+
+	fmt.Fprintln(tw, "\nDISTRICT\tREGION\tCOUNTIES\tSUBCOUNTIES\tPARISHES\tVILLAGES\tMISSING")
+	for _, d := range s.Districts {
+		missing := make([]string, len(d.Missing))
+		for i, level := range d.Missing {
+			missing[i] = level.String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Name, d.RegionName, counts(d.Counts), strings.Join(missing, ", "))
+	}
+
+	tw.Flush()
+	return b.String()
+}
+
+// of returns the count for a specific level
+func (c Counts) of(level Level) int {
+	switch level {
+	case LevelDistrict:
+		return c.Districts
+	case LevelCounty:
+		return c.Counties
+	case LevelSubcounty:
+		return c.Subcounties
+	case LevelParish:
+		return c.Parishes
+	case LevelVillage:
+		return c.Villages
+	}
+	return 0
+}
+
+func fanOutOf[T any](level Level, parents []T, children func(T) int) FanOut {
+	f := FanOut{Level: level, Parents: len(parents)}
+	if len(parents) == 0 {
+		return f
+	}
+
+	sizes := make([]int, len(parents))
+	for i, p := range parents {
+		sizes[i] = children(p)
+	}
+	sort.Ints(sizes)
+
+	f.Min = sizes[0]
+	f.Max = sizes[len(sizes)-1]
+	if mid := len(sizes) / 2; len(sizes)%2 == 1 {
+		f.Median = float64(sizes[mid])
+	} else {
+		f.Median = float64(sizes[mid-1]+sizes[mid]) / 2
+	}
+
+	return f
+}
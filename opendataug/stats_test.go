@@ -0,0 +1,143 @@
+package opendataug
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func statsDataset() *Dataset {
+	ds := testDataset()
+	ds.Districts = append(ds.Districts, District{ID: "district-3", Name: "Mbarara", RegionID: "region-2", RegionName: "Western"})
+	return ds
+}
+
+func TestNewStatistics(t *testing.T) {
+	s := NewStatistics(NewAdminTree(statsDataset()))
+
+	expectedTotals := Counts{Districts: 3, Counties: 3, Subcounties: 3, Parishes: 4, Villages: 6}
+	if s.Totals != expectedTotals {
+		t.Errorf("Expected totals %+v, got %+v", expectedTotals, s.Totals)
+	}
+
+	expectedFanOut := []FanOut{
+		{Level: LevelDistrict, Parents: 3, Min: 0, Max: 2, Median: 1},
+		{Level: LevelCounty, Parents: 3, Min: 1, Max: 1, Median: 1},
+		{Level: LevelSubcounty, Parents: 3, Min: 1, Max: 2, Median: 1},
+		{Level: LevelParish, Parents: 4, Min: 1, Max: 2, Median: 1},
+	}
+	if !reflect.DeepEqual(s.FanOut, expectedFanOut) {
+		t.Errorf("Expected fan-out %+v, got %+v", expectedFanOut, s.FanOut)
+	}
+
+	expectedRegions := []RegionStats{
+		{ID: "region-1", Name: "Central", Counts: Counts{Districts: 2, Counties: 3, Subcounties: 3, Parishes: 4, Villages: 5}},
+		{ID: "region-2", Name: "Western", Counts: Counts{Districts: 1}},
+	}
+	if !reflect.DeepEqual(s.Regions, expectedRegions) {
+		t.Errorf("Expected regions %+v, got %+v", expectedRegions, s.Regions)
+	}
+
+	expectedDistrict := DistrictStats{
+		ID:         "district-1",
+		Name:       "Kampala",
+		RegionName: "Central",
+		Counts:     Counts{Counties: 2, Subcounties: 2, Parishes: 3, Villages: 4},
+	}
+	if !reflect.DeepEqual(s.Districts[0], expectedDistrict) {
+		t.Errorf("Expected district %+v, got %+v", expectedDistrict, s.Districts[0])
+	}
+
+	incomplete := s.Incomplete()
+	expectedMissing := []Level{LevelCounty, LevelSubcounty, LevelParish, LevelVillage}
+	if len(incomplete) != 1 || incomplete[0].ID != "district-3" || !reflect.DeepEqual(incomplete[0].Missing, expectedMissing) {
+		t.Errorf("Expected district-3 to miss %v, got %+v", expectedMissing, incomplete)
+	}
+}
+
+func TestFanOutMedian(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    []int
+		expected FanOut
+	}{
+		{name: "Empty", sizes: nil, expected: FanOut{Level: LevelParish}},
+		{name: "Odd", sizes: []int{5, 1, 3}, expected: FanOut{Level: LevelParish, Parents: 3, Min: 1, Max: 5, Median: 3}},
+		{name: "Even", sizes: []int{4, 1, 2, 7}, expected: FanOut{Level: LevelParish, Parents: 4, Min: 1, Max: 7, Median: 3}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := fanOutOf(LevelParish, tc.sizes, func(n int) int { return n })
+			if got != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestComputeStatistics(t *testing.T) {
+	server, client := TestRoutesServer(t, datasetRoutes(t, statsDataset()))
+	defer server.Close()
+
+	s, err := ComputeStatistics(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if s.Totals.Districts != 3 || len(s.Districts) != 3 {
+		t.Errorf("Expected 3 districts, got %+v", s.Totals)
+	}
+}
+
+func TestStatisticsOutput(t *testing.T) {
+	s := NewStatistics(NewAdminTree(statsDataset()))
+
+	var buf bytes.Buffer
+	if err := s.WriteJSON(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var decoded Statistics
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, s) {
+		t.Errorf("Expected %+v, got %+v", s, decoded)
+	}
+
+	buf.Reset()
+	if err := s.WriteCSV(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got %v", err)
+	}
+	// header, total, 2 regions and 3 districts
+	if len(records) != 7 {
+		t.Fatalf("Expected 7 records, got %d", len(records))
+	}
+	expectedHeader := []string{"scope", "id", "name", "region", "districts", "counties", "subcounties", "parishes", "villages", "missing"}
+	if !reflect.DeepEqual(records[0], expectedHeader) {
+		t.Errorf("Expected %v, got %v", expectedHeader, records[0])
+	}
+	expectedRegion := []string{"region", "region-2", "Western", "", "1", "0", "0", "0", "0", ""}
+	if !reflect.DeepEqual(records[3], expectedRegion) {
+		t.Errorf("Expected %v, got %v", expectedRegion, records[3])
+	}
+	expectedRow := []string{"district", "district-3", "Mbarara", "Western", "0", "0", "0", "0", "0", "county;subcounty;parish;village"}
+	if !reflect.DeepEqual(records[6], expectedRow) {
+		t.Errorf("Expected %v, got %v", expectedRow, records[6])
+	}
+
+	table := s.Table()
+	for _, want := range []string{"district -> county", "Western", "Mbarara", "county, subcounty, parish, village"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("Expected table to contain %q, got:\n%s", want, table)
+		}
+	}
+}
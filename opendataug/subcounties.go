@@ -1,32 +1,34 @@
 package opendataug
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
 
 // GetSubcounties retrieves all subcounties
 func (c *Client) GetSubcounties() ([]Subcounty, error) {
-	var response struct {
-		Data []Subcounty `json:"data"`
-	}
-
-	err := c.doRequest(http.MethodGet, "/subcounties", &response)
-	if err != nil {
-		return nil, err
-	}
+	return c.GetSubcountiesContext(context.Background())
+}
 
-	return response.Data, nil
+// GetSubcountiesContext retrieves all subcounties using ctx
+func (c *Client) GetSubcountiesContext(ctx context.Context) ([]Subcounty, error) {
+	return getList[Subcounty](ctx, c, "/subcounties")
 }
 
 // GetSubcounty retrieves a specific subcounty by ID
-func (c *Client) GetSubcounty(id string) (*Subcounty, error) {
+func (c *Client) GetSubcounty(id SubcountyID) (*Subcounty, error) {
+	return c.GetSubcountyContext(context.Background(), id)
+}
+
+// GetSubcountyContext retrieves a specific subcounty by ID using ctx
+func (c *Client) GetSubcountyContext(ctx context.Context, id SubcountyID) (*Subcounty, error) {
 	var response struct {
 		Data Subcounty `json:"data"`
 	}
 
 	path := fmt.Sprintf("/subcounties/%s", id)
-	err := c.doRequest(http.MethodGet, path, &response)
+	err := c.doRequestContext(ctx, http.MethodGet, path, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -35,16 +37,12 @@ func (c *Client) GetSubcounty(id string) (*Subcounty, error) {
 }
 
 // GetSubcountiesByCounty retrieves all subcounties in a specific county
-func (c *Client) GetSubcountiesByCounty(countyID string) ([]Subcounty, error) {
-	var response struct {
-		Data []Subcounty `json:"data"`
-	}
+func (c *Client) GetSubcountiesByCounty(countyID CountyID) ([]Subcounty, error) {
+	return c.GetSubcountiesByCountyContext(context.Background(), countyID)
+}
 
+// GetSubcountiesByCountyContext retrieves all subcounties in a specific county using ctx
+func (c *Client) GetSubcountiesByCountyContext(ctx context.Context, countyID CountyID) ([]Subcounty, error) {
 	path := fmt.Sprintf("/counties/%s/subcounties", countyID)
-	err := c.doRequest(http.MethodGet, path, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return response.Data, nil
+	return getList[Subcounty](ctx, c, path)
 }
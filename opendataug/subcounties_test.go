@@ -55,7 +55,7 @@ func TestGetSubcounties(t *testing.T) {
 func TestGetSubcounty(t *testing.T) {
 	tests := []struct {
 		name           string
-		subcountyID    string
+		subcountyID    SubcountyID
 		expectedPath   string
 		response       string
 		expectedResult *Subcounty
@@ -118,7 +118,7 @@ func TestGetSubcounty(t *testing.T) {
 func TestGetSubcountiesByCounty(t *testing.T) {
 	tests := []struct {
 		name           string
-		countyID       string
+		countyID       CountyID
 		expectedPath   string
 		response       string
 		expectedResult []Subcounty
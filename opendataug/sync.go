@@ -0,0 +1,671 @@
+package opendataug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tombstone records a unit that was deleted upstream, so that consumers of
+// the local copy can remove it too
+type Tombstone struct {
+	Level     Level     `json:"level"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// SyncState is the local copy of the hierarchy kept by a Syncer
+type SyncState struct {
+	Dataset    Dataset     `json:"dataset"`
+	Tombstones []Tombstone `json:"tombstones"`
+	// Marks holds the high-water mark of the updated_at timestamps seen at
+	// each level below district
+	Marks    map[Level]time.Time `json:"marks"`
+	LastFull time.Time           `json:"last_full"`
+	LastSync time.Time           `json:"last_sync"`
+	// NextCheck is the position of the next district whose subtree is
+	// checked during a delta sync
+	NextCheck int `json:"next_check"`
+}
+
+func (s *SyncState) clone() *SyncState {
+	c := *s
+	c.Dataset = Dataset{
+		Districts:   append([]District(nil), s.Dataset.Districts...),
+		Counties:    append([]County(nil), s.Dataset.Counties...),
+		Subcounties: append([]Subcounty(nil), s.Dataset.Subcounties...),
+		Parishes:    append([]Parish(nil), s.Dataset.Parishes...),
+		Villages:    append([]Village(nil), s.Dataset.Villages...),
+	}
+	c.Tombstones = append([]Tombstone(nil), s.Tombstones...)
+	c.Marks = make(map[Level]time.Time, len(s.Marks))
+	for level, mark := range s.Marks {
+		c.Marks[level] = mark
+	}
+	return &c
+}
+
+// Store persists the state of a Syncer. Load returns a nil state when nothing
+// has been saved yet.
+type Store interface {
+	Load(ctx context.Context) (*SyncState, error)
+	Save(ctx context.Context, state *SyncState) error
+}
+
+// MemoryStore keeps the sync state in memory
+type MemoryStore struct {
+	mu    sync.Mutex
+	state *SyncState
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Load returns a copy of the saved state
+func (m *MemoryStore) Load(ctx context.Context) (*SyncState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state == nil {
+		return nil, nil
+	}
+	return m.state.clone(), nil
+}
+
+// Save keeps a copy of state
+func (m *MemoryStore) Save(ctx context.Context, state *SyncState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.state = state.clone()
+	return nil
+}
+
+// FileStore keeps the sync state in a JSON file
+type FileStore struct {
+	path string
+}
+
+// NewFileStore returns a FileStore saving to path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads the state from the file
+func (f *FileStore) Load(ctx context.Context) (*SyncState, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state SyncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Save replaces the file with state
+func (f *FileStore) Save(ctx context.Context, state *SyncState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.path, data)
+}
+
+// SyncResult describes the changes applied by one sync
+type SyncResult struct {
+	Full       bool        `json:"full"`
+	Added      int         `json:"added"`
+	Updated    int         `json:"updated"`
+	Removed    int         `json:"removed"`
+	Tombstones []Tombstone `json:"tombstones,omitempty"`
+}
+
+// SyncStatus reports the progress of a Syncer
+type SyncStatus struct {
+	Running     bool       `json:"running"`
+	LastAttempt time.Time  `json:"last_attempt"`
+	LastSuccess time.Time  `json:"last_success"`
+	LastFull    time.Time  `json:"last_full"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastResult  SyncResult `json:"last_result"`
+	Counts      Counts     `json:"counts"`
+	Tombstones  int        `json:"tombstones"`
+}
+
+// defaultFullSyncInterval is how often a Syncer reloads everything
+const defaultFullSyncInterval = 24 * time.Hour
+
+// Syncer keeps a local copy of the hierarchy in a Store up to date. The
+// first sync loads every level. Later syncs are deltas: they list the
+// districts, fetch the units of the other levels updated since the last sync
+// and re-fetch the subtrees of a few districts in turn. Units that have
+// disappeared from a re-fetched subtree, and whose lookup by ID now fails, are
+// removed and tombstoned. Deletions outside the checked subtrees are caught by
+// the periodic full sync. Tombstones are kept until their unit reappears or,
+// with WithTombstoneRetention, until they expire.
+type Syncer struct {
+	api                API
+	store              Store
+	fullSyncInterval   time.Duration
+	subtreeChecks      int
+	tombstoneRetention time.Duration
+	now                func() time.Time
+
+	mu     sync.Mutex
+	status SyncStatus
+}
+
+// SyncOption configures a Syncer
+type SyncOption func(*Syncer)
+
+// WithFullSyncInterval sets how often the Syncer reloads the whole hierarchy.
+// Zero disables full syncs after the first.
+func WithFullSyncInterval(d time.Duration) SyncOption {
+	return func(s *Syncer) {
+		if d >= 0 {
+			s.fullSyncInterval = d
+		}
+	}
+}
+
+// WithSubtreeChecks sets how many district subtrees each delta sync
+// re-fetches to find deletions. Zero disables subtree checks.
+func WithSubtreeChecks(n int) SyncOption {
+	return func(s *Syncer) {
+		if n >= 0 {
+			s.subtreeChecks = n
+		}
+	}
+}
+
+// WithTombstoneRetention sets how long the Syncer keeps the tombstone of a
+// deleted unit. Zero, the default, keeps tombstones forever.
+func WithTombstoneRetention(d time.Duration) SyncOption {
+	return func(s *Syncer) {
+		if d >= 0 {
+			s.tombstoneRetention = d
+		}
+	}
+}
+
+// NewSyncer returns a Syncer that copies the hierarchy from api into store
+func NewSyncer(api API, store Store, opts ...SyncOption) *Syncer {
+	s := &Syncer{
+		api:              api,
+		store:            store,
+		fullSyncInterval: defaultFullSyncInterval,
+		subtreeChecks:    1,
+		now:              time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Status returns the status of the Syncer
+func (s *Syncer) Status() SyncStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	status.LastResult.Tombstones = append([]Tombstone(nil), status.LastResult.Tombstones...)
+	return status
+}
+
+// Dataset returns the local copy of the hierarchy
+func (s *Syncer) Dataset(ctx context.Context) (*Dataset, error) {
+	state, err := s.store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return &Dataset{}, nil
+	}
+	return &state.Dataset, nil
+}
+
+// Run syncs immediately and then every interval until ctx is done. Failed
+// syncs are reported through Status and retried at the next interval. The
+// interval must be positive.
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("opendataug: sync interval must be positive, got %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = s.Sync(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync brings the local copy up to date, running a full sync when none has
+// run yet or the full sync interval has passed, and a delta sync otherwise
+func (s *Syncer) Sync(ctx context.Context) (*SyncResult, error) {
+	now := s.now().UTC()
+
+	s.mu.Lock()
+	if s.status.Running {
+		s.mu.Unlock()
+		return nil, errors.New("opendataug: sync already running")
+	}
+	s.status.Running = true
+	s.status.LastAttempt = now
+	s.mu.Unlock()
+
+	result, state, err := s.sync(ctx, now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.Running = false
+	if err != nil {
+		s.status.LastError = err.Error()
+		return nil, err
+	}
+
+	s.status.LastError = ""
+	s.status.LastSuccess = now
+	s.status.LastFull = state.LastFull
+	s.status.LastResult = *result
+	s.status.Counts = Counts{
+		Districts:   len(state.Dataset.Districts),
+		Counties:    len(state.Dataset.Counties),
+		Subcounties: len(state.Dataset.Subcounties),
+		Parishes:    len(state.Dataset.Parishes),
+		Villages:    len(state.Dataset.Villages),
+	}
+	s.status.Tombstones = len(state.Tombstones)
+
+	return result, nil
+}
+
+func (s *Syncer) sync(ctx context.Context, now time.Time) (*SyncResult, *SyncState, error) {
+	state, err := s.store.Load(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	full := state == nil || state.LastFull.IsZero() ||
+		(s.fullSyncInterval > 0 && now.Sub(state.LastFull) >= s.fullSyncInterval)
+	if state == nil {
+		state = &SyncState{}
+	}
+
+	set := newSyncSet(state, now)
+	set.result.Full = full
+
+	if full {
+		err = s.fullSync(ctx, set, state)
+	} else {
+		err = s.deltaSync(ctx, set, state)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.tombstoneRetention > 0 {
+		set.expire(now.Add(-s.tombstoneRetention))
+	}
+	set.apply(state)
+	state.LastSync = now
+	if full {
+		state.LastFull = now
+	}
+
+	if err := s.store.Save(ctx, state); err != nil {
+		return nil, nil, err
+	}
+
+	return set.result, state, nil
+}
+
+func (s *Syncer) fullSync(ctx context.Context, set *syncSet, state *SyncState) error {
+	fresh, err := LoadDataset(ctx, s.api)
+	if err != nil {
+		return err
+	}
+
+	scope := make([]UnitKey, 0, len(set.units))
+	for key := range set.units {
+		scope = append(scope, key)
+	}
+	set.reconcile(scope, fresh)
+
+	state.Marks = map[Level]time.Time{
+		LevelCounty:    latestUpdate(fresh.Counties, func(c County) Timestamp { return c.UpdatedAt }),
+		LevelSubcounty: latestUpdate(fresh.Subcounties, func(s Subcounty) Timestamp { return s.UpdatedAt }),
+		LevelParish:    latestUpdate(fresh.Parishes, func(p Parish) Timestamp { return p.UpdatedAt }),
+		LevelVillage:   latestUpdate(fresh.Villages, func(v Village) Timestamp { return v.UpdatedAt }),
+	}
+
+	return nil
+}
+
+// deltaSync fetches the units updated since the saved marks. The updated-since
+// methods return units updated exactly at a mark again, so units written in
+// the same instant as the last one seen are not missed; upserting by key
+// merges the repeats, and only units whose content changed count as updated.
+func (s *Syncer) deltaSync(ctx context.Context, set *syncSet, state *SyncState) error {
+	if state.Marks == nil {
+		state.Marks = make(map[Level]time.Time)
+	}
+
+	counties, mark, err := s.api.GetCountiesUpdatedSince(ctx, state.Marks[LevelCounty])
+	if err != nil {
+		return err
+	}
+	set.upsertAll(units(counties))
+	state.Marks[LevelCounty] = mark
+
+	subcounties, mark, err := s.api.GetSubcountiesUpdatedSince(ctx, state.Marks[LevelSubcounty])
+	if err != nil {
+		return err
+	}
+	set.upsertAll(units(subcounties))
+	state.Marks[LevelSubcounty] = mark
+
+	parishes, mark, err := s.api.GetParishesUpdatedSince(ctx, state.Marks[LevelParish])
+	if err != nil {
+		return err
+	}
+	set.upsertAll(units(parishes))
+	state.Marks[LevelParish] = mark
+
+	villages, mark, err := s.api.GetVillagesUpdatedSince(ctx, state.Marks[LevelVillage])
+	if err != nil {
+		return err
+	}
+	set.upsertAll(units(villages))
+	state.Marks[LevelVillage] = mark
+
+	// Districts have no updated_at, but there are few enough of them to list
+	// every time, which also reveals deleted districts. This runs after the
+	// updates above so that units moved out of a deleted district are kept.
+	districts, err := s.api.GetDistrictsContext(ctx)
+	if err != nil {
+		return err
+	}
+	tree := NewAdminTree(set.dataset())
+	listed := make(map[DistrictID]bool, len(districts))
+	for _, d := range districts {
+		listed[d.ID] = true
+		set.upsert(d)
+	}
+	for _, d := range tree.Districts() {
+		if listed[d.ID] {
+			continue
+		}
+		for _, u := range tree.Subtree(d) {
+			set.remove(KeyOf(u))
+		}
+	}
+
+	return s.checkSubtrees(ctx, set, state)
+}
+
+// checkSubtrees re-fetches the next few district subtrees and removes units
+// that are gone from them. A unit missing from its old subtree may have moved
+// to a district that was not checked, so it is only removed once looking it
+// up by ID fails.
+func (s *Syncer) checkSubtrees(ctx context.Context, set *syncSet, state *SyncState) error {
+	tree := NewAdminTree(set.dataset())
+	districts := tree.Districts()
+	if s.subtreeChecks == 0 || len(districts) == 0 {
+		return nil
+	}
+
+	n := min(s.subtreeChecks, len(districts))
+	start := state.NextCheck % len(districts)
+	var keys []UnitKey
+	for i := range n {
+		keys = append(keys, KeyOf(districts[(start+i)%len(districts)]))
+	}
+	state.NextCheck = (start + n) % len(districts)
+
+	fresh, err := FetchBranches(ctx, s.api, keys)
+	if err != nil {
+		return err
+	}
+
+	present := make(map[UnitKey]bool)
+	freshUnits := fresh.units()
+	for _, level := range Levels {
+		for _, u := range freshUnits[level] {
+			present[KeyOf(u)] = true
+			set.upsert(u)
+		}
+	}
+
+	for _, key := range keys {
+		root, _ := tree.Unit(key.Level, key.ID)
+		for _, u := range tree.Subtree(root) {
+			if present[KeyOf(u)] {
+				continue
+			}
+			moved, err := fetchUnit(ctx, s.api, KeyOf(u))
+			switch {
+			case isNotFound(err):
+				set.remove(KeyOf(u))
+			case err != nil:
+				return err
+			default:
+				set.upsert(moved)
+			}
+		}
+	}
+
+	return nil
+}
+
+// syncSet holds the units and tombstones of a state while a sync changes them
+type syncSet struct {
+	now        time.Time
+	units      map[UnitKey]AdminUnit
+	tombstones map[UnitKey]Tombstone
+	result     *SyncResult
+}
+
+func newSyncSet(state *SyncState, now time.Time) *syncSet {
+	set := &syncSet{
+		now:        now,
+		units:      make(map[UnitKey]AdminUnit),
+		tombstones: make(map[UnitKey]Tombstone),
+		result:     &SyncResult{},
+	}
+
+	for _, us := range state.Dataset.units() {
+		for _, u := range us {
+			set.units[KeyOf(u)] = u
+		}
+	}
+	for _, t := range state.Tombstones {
+		set.tombstones[UnitKey{Level: t.Level, ID: t.ID}] = t
+	}
+
+	return set
+}
+
+// upsert adds or replaces u. A unit that reappears loses its tombstone.
+func (s *syncSet) upsert(u AdminUnit) {
+	key := KeyOf(u)
+	if old, ok := s.units[key]; !ok {
+		s.result.Added++
+	} else if UnitHash(old) != UnitHash(u) {
+		s.result.Updated++
+	}
+	s.units[key] = u
+	delete(s.tombstones, key)
+}
+
+func (s *syncSet) upsertAll(units []AdminUnit) {
+	for _, u := range units {
+		s.upsert(u)
+	}
+}
+
+// remove deletes the unit with the given key and tombstones it
+func (s *syncSet) remove(key UnitKey) {
+	u, ok := s.units[key]
+	if !ok {
+		return
+	}
+	delete(s.units, key)
+
+	t := Tombstone{Level: key.Level, ID: key.ID, Name: u.UnitName(), DeletedAt: s.now}
+	s.tombstones[key] = t
+	s.result.Removed++
+	s.result.Tombstones = append(s.result.Tombstones, t)
+}
+
+// expire drops the tombstones of units deleted before cutoff
+func (s *syncSet) expire(cutoff time.Time) {
+	for key, t := range s.tombstones {
+		if t.DeletedAt.Before(cutoff) {
+			delete(s.tombstones, key)
+		}
+	}
+}
+
+// reconcile makes the units in scope match fresh, which must hold every unit
+// that should remain in scope
+func (s *syncSet) reconcile(scope []UnitKey, fresh *Dataset) {
+	present := make(map[UnitKey]bool)
+	freshUnits := fresh.units()
+	for _, level := range Levels {
+		for _, u := range freshUnits[level] {
+			present[KeyOf(u)] = true
+			s.upsert(u)
+		}
+	}
+
+	sortUnitKeys(scope)
+	for _, key := range scope {
+		if !present[key] {
+			s.remove(key)
+		}
+	}
+}
+
+// dataset returns the units of the set ordered by ID within each level
+func (s *syncSet) dataset() *Dataset {
+	keys := make([]UnitKey, 0, len(s.units))
+	for key := range s.units {
+		keys = append(keys, key)
+	}
+	sortUnitKeys(keys)
+
+	ds := &Dataset{}
+	for _, key := range keys {
+		switch u := s.units[key].(type) {
+		case District:
+			ds.Districts = append(ds.Districts, u)
+		case County:
+			ds.Counties = append(ds.Counties, u)
+		case Subcounty:
+			ds.Subcounties = append(ds.Subcounties, u)
+		case Parish:
+			ds.Parishes = append(ds.Parishes, u)
+		case Village:
+			ds.Villages = append(ds.Villages, u)
+		}
+	}
+	return ds
+}
+
+// apply stores the units and tombstones of the set in state
+func (s *syncSet) apply(state *SyncState) {
+	state.Dataset = *s.dataset()
+
+	state.Tombstones = make([]Tombstone, 0, len(s.tombstones))
+	for _, t := range s.tombstones {
+		state.Tombstones = append(state.Tombstones, t)
+	}
+	sort.Slice(state.Tombstones, func(i, j int) bool {
+		a, b := state.Tombstones[i], state.Tombstones[j]
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		return a.ID < b.ID
+	})
+}
+
+func sortUnitKeys(keys []UnitKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Level != keys[j].Level {
+			return keys[i].Level < keys[j].Level
+		}
+		return keys[i].ID < keys[j].ID
+	})
+}
+
+// fetchUnit retrieves a single unit by key
+func fetchUnit(ctx context.Context, api API, key UnitKey) (AdminUnit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	switch key.Level {
+	case LevelDistrict:
+		u, err := api.GetDistrictContext(ctx, DistrictID(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	case LevelCounty:
+		u, err := api.GetCountyContext(ctx, CountyID(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	case LevelSubcounty:
+		u, err := api.GetSubcountyContext(ctx, SubcountyID(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	case LevelParish:
+		u, err := api.GetParishContext(ctx, ParishID(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	case LevelVillage:
+		u, err := api.GetVillageContext(ctx, VillageID(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	}
+	return nil, fmt.Errorf("opendataug: cannot fetch unit of invalid level %d", int(key.Level))
+}
+
+// latestUpdate returns the latest updated_at of items
+func latestUpdate[T any](items []T, updatedAt func(T) Timestamp) time.Time {
+	_, mark := filterUpdatedSince(items, time.Time{}, updatedAt)
+	return mark
+}
@@ -0,0 +1,335 @@
+package opendataug
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// swapAPI lets a test change the data served to a Syncer between syncs
+type swapAPI struct {
+	API
+}
+
+func tombstoneIDs(tombstones []Tombstone) []string {
+	ids := make([]string, len(tombstones))
+	for i, t := range tombstones {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+func TestSyncerFullSync(t *testing.T) {
+	store := NewMemoryStore()
+	s := NewSyncer(NewOfflineClient(testDataset()), store)
+
+	result, err := s.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.Full || result.Added != 18 || result.Updated != 0 || result.Removed != 0 {
+		t.Errorf("Expected a full sync adding 18 units, got %+v", result)
+	}
+
+	status := s.Status()
+	expectedCounts := Counts{Districts: 2, Counties: 3, Subcounties: 3, Parishes: 4, Villages: 6}
+	if status.Counts != expectedCounts || status.LastSuccess.IsZero() || status.LastFull != status.LastSuccess {
+		t.Errorf("Expected counts %+v after a full sync, got %+v", expectedCounts, status)
+	}
+
+	ds, err := s.Dataset(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(ds.Parishes, testDataset().Parishes) {
+		t.Errorf("Expected %+v, got %+v", testDataset().Parishes, ds.Parishes)
+	}
+}
+
+func TestSyncerDeltaSync(t *testing.T) {
+	ctx := context.Background()
+	api := &swapAPI{API: NewOfflineClient(testDataset())}
+	s := NewSyncer(api, NewMemoryStore(), WithFullSyncInterval(0), WithSubtreeChecks(2))
+
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	updated := NewTimestamp(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	ds := testDataset()
+	ds.Villages[2].Name = "Ntinda Market"
+	ds.Villages[2].UpdatedAt = updated
+	ds.Villages = append(ds.Villages[:3], ds.Villages[4:]...)
+	ds.Counties = append(ds.Counties, County{ID: "county-4", Name: "Busiro", DistrictID: "district-2", UpdatedAt: updated})
+	api.API = NewOfflineClient(ds)
+
+	result, err := s.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Full || result.Added != 1 || result.Updated != 1 || result.Removed != 1 {
+		t.Errorf("Expected a delta adding, updating and removing one unit, got %+v", result)
+	}
+	if ids := tombstoneIDs(result.Tombstones); !reflect.DeepEqual(ids, []string{"village-4"}) {
+		t.Errorf("Expected village-4 to be tombstoned, got %v", ids)
+	}
+
+	local, _ := s.Dataset(ctx)
+	if v, ok := NewAdminTree(local).Village("village-3"); !ok || v.Name != "Ntinda Market" {
+		t.Errorf("Expected village-3 to be renamed, got %+v", v)
+	}
+	if s.Status().Tombstones != 1 {
+		t.Errorf("Expected 1 tombstone, got %d", s.Status().Tombstones)
+	}
+
+	// A unit that reappears loses its tombstone, while county-4 is gone
+	// again
+	api.API = NewOfflineClient(testDataset())
+	result, err = s.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ids := tombstoneIDs(result.Tombstones); !reflect.DeepEqual(ids, []string{"county-4"}) {
+		t.Errorf("Expected county-4 to be tombstoned, got %v", ids)
+	}
+	if s.Status().Tombstones != 1 {
+		t.Errorf("Expected 1 tombstone, got %d", s.Status().Tombstones)
+	}
+}
+
+func TestSyncerDeletedDistrict(t *testing.T) {
+	ctx := context.Background()
+	api := &swapAPI{API: NewOfflineClient(testDataset())}
+	store := NewMemoryStore()
+	s := NewSyncer(api, store, WithFullSyncInterval(0), WithSubtreeChecks(0))
+
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ds := testDataset()
+	ds.Districts = ds.Districts[:1]
+	api.API = NewOfflineClient(ds)
+
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	state, _ := store.Load(ctx)
+	expected := []string{"district-2", "county-3", "subcounty-3", "parish-4", "village-5"}
+	if ids := tombstoneIDs(state.Tombstones); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected tombstones %v, got %v", expected, ids)
+	}
+}
+
+func TestSyncerSubtreeCheckKeepsMovedUnits(t *testing.T) {
+	ctx := context.Background()
+	stamp := NewTimestamp(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	dataset := func() *Dataset {
+		ds := testDataset()
+		for i := range ds.Parishes {
+			ds.Parishes[i].UpdatedAt = stamp
+		}
+		return ds
+	}
+
+	api := &swapAPI{API: NewOfflineClient(dataset())}
+	s := NewSyncer(api, NewMemoryStore(), WithFullSyncInterval(0), WithSubtreeChecks(1))
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// parish-2 moves to another district without its updated_at changing,
+	// so only the subtree check of district-1 notices it
+	ds := dataset()
+	ds.Parishes[1].SubcountyID = "subcounty-3"
+	api.API = NewOfflineClient(ds)
+
+	result, err := s.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Removed != 0 || result.Updated != 1 {
+		t.Errorf("Expected parish-2 to be updated rather than removed, got %+v", result)
+	}
+
+	local, _ := s.Dataset(ctx)
+	if p, ok := NewAdminTree(local).Parish("parish-2"); !ok || p.SubcountyID != "subcounty-3" {
+		t.Errorf("Expected parish-2 in subcounty-3, got %+v", p)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := NewFileStore(path).Load(ctx)
+	if err != nil || state != nil {
+		t.Fatalf("Expected no state, got %+v, %v", state, err)
+	}
+
+	s := NewSyncer(NewOfflineClient(testDataset()), NewFileStore(path))
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	state, err = NewFileStore(path).Load(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(state.Dataset.Villages, NewAdminTree(testDataset()).Villages()) {
+		t.Errorf("Expected %+v, got %+v", testDataset().Villages, state.Dataset.Villages)
+	}
+	if state.LastFull.IsZero() || state.Marks == nil {
+		t.Errorf("Expected the sync state to be saved, got %+v", state)
+	}
+}
+
+func TestSyncerStatusError(t *testing.T) {
+	server, client := TestRoutesServer(t, map[string]string{})
+	defer server.Close()
+
+	s := NewSyncer(client, NewMemoryStore())
+	if _, err := s.Sync(context.Background()); err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+
+	status := s.Status()
+	if status.LastError == "" || !status.LastSuccess.IsZero() || status.Running {
+		t.Errorf("Expected the failure to be reported, got %+v", status)
+	}
+}
+
+func TestSyncerRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	s := NewSyncer(NewOfflineClient(testDataset()), NewMemoryStore())
+	if err := s.Run(ctx, 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if s.Status().LastSuccess.IsZero() {
+		t.Error("Expected at least one successful sync")
+	}
+}
+
+func TestSyncerRunInvalidInterval(t *testing.T) {
+	s := NewSyncer(NewOfflineClient(testDataset()), NewMemoryStore())
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := s.Run(context.Background(), interval); err == nil {
+			t.Errorf("Expected an error for interval %v, got nil", interval)
+		}
+	}
+
+	if !s.Status().LastAttempt.IsZero() {
+		t.Error("Expected no sync to be attempted")
+	}
+}
+
+func TestSyncerTombstoneRetention(t *testing.T) {
+	ctx := context.Background()
+	api := &swapAPI{API: NewOfflineClient(testDataset())}
+	store := NewMemoryStore()
+	s := NewSyncer(api, store, WithFullSyncInterval(0), WithSubtreeChecks(0), WithTombstoneRetention(24*time.Hour))
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Drop Wakiso together with its subtree, so that no delta brings any of
+	// it back
+	ds := testDataset()
+	ds.Districts = ds.Districts[:1]
+	ds.Counties = ds.Counties[:2]
+	ds.Subcounties = ds.Subcounties[:2]
+	ds.Parishes = ds.Parishes[:3]
+	ds.Villages = append(ds.Villages[:4], ds.Villages[5:]...)
+	api.API = NewOfflineClient(ds)
+
+	now = now.Add(time.Hour)
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := s.Status().Tombstones; n != 5 {
+		t.Errorf("Expected 5 tombstones, got %d", n)
+	}
+
+	now = now.Add(24 * time.Hour)
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := s.Status().Tombstones; n != 5 {
+		t.Errorf("Expected tombstones to be kept for the retention period, got %d", n)
+	}
+
+	now = now.Add(time.Second)
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	state, _ := store.Load(ctx)
+	if len(state.Tombstones) != 0 {
+		t.Errorf("Expected expired tombstones to be dropped, got %v", tombstoneIDs(state.Tombstones))
+	}
+}
+
+func TestSyncerDeltaSyncMarkBoundary(t *testing.T) {
+	ctx := context.Background()
+	stamp := NewTimestamp(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	ds := testDataset()
+	ds.Counties[0].UpdatedAt = stamp
+	api := &swapAPI{API: NewOfflineClient(ds)}
+	s := NewSyncer(api, NewMemoryStore(), WithFullSyncInterval(0), WithSubtreeChecks(0))
+
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// county-2 is updated in the same instant as the saved mark
+	ds = testDataset()
+	ds.Counties[0].UpdatedAt = stamp
+	ds.Counties[1].Name = "Kawempe North"
+	ds.Counties[1].UpdatedAt = stamp
+	api.API = NewOfflineClient(ds)
+
+	result, err := s.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Added != 0 || result.Updated != 1 {
+		t.Errorf("Expected only county-2 to be updated, got %+v", result)
+	}
+
+	local, _ := s.Dataset(ctx)
+	if c, ok := NewAdminTree(local).County("county-2"); !ok || c.Name != "Kawempe North" {
+		t.Errorf("Expected county-2 to be renamed, got %+v", c)
+	}
+	if len(local.Counties) != 3 {
+		t.Errorf("Expected 3 counties, got %+v", local.Counties)
+	}
+}
+
+func TestSyncerCancelledSync(t *testing.T) {
+	server, client, hits := unitServer(t, testDataset())
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The sync goes through a non-Client API, which must still pass ctx on
+	s := NewSyncer(&swapAPI{API: client}, NewMemoryStore())
+	if _, err := s.Sync(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if n := hits("/districts"); n != 0 {
+		t.Errorf("Expected no requests after cancellation, got %d", n)
+	}
+}
@@ -27,3 +27,30 @@ func TestServer(t *testing.T, expectedPath string, response string) (*httptest.S
 
 	return server, client
 }
+
+// TestRoutesServer serves a fixed response for each path in routes and
+// responds with 404 Not Found to any other path
+func TestRoutesServer(t *testing.T, routes map[string]string) (*httptest.Server, *Client) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "test-api-key" {
+			t.Errorf("Expected API key header to be set")
+		}
+
+		response, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "Not found"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(response))
+	}))
+
+	client := NewClient("test-api-key")
+
+	baseURL = server.URL
+
+	return server, client
+}
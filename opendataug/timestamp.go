@@ -0,0 +1,144 @@
+package opendataug
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// timestampLayouts are the formats the API has been seen to use for
+// created_at and updated_at
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Timestamp is a point in time reported by the API. It accepts RFC 3339
+// timestamps, timestamps without a zone, dates and empty or null values, and
+// marshals back in the layout it was parsed from. The output denotes the same
+// time but is not always byte-identical to the input: RFC 3339 fractional
+// seconds lose their trailing zeros, so "2024-03-02T10:00:00.000000Z" comes
+// back as "2024-03-02T10:00:00Z". The zero Timestamp stands for a missing
+// value.
+//
+// Decoding never fails on an unrecognised format, so that one odd value does
+// not fail a whole list: the time is left zero, as for a missing value, and
+// the original text is kept and returned by Raw and String.
+type Timestamp struct {
+	time.Time
+	layout string
+	raw    string
+}
+
+// NewTimestamp returns a Timestamp for t that marshals as RFC 3339
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// ParseTimestamp parses s in any of the layouts used by the API. An empty
+// string yields the zero Timestamp.
+func ParseTimestamp(s string) (Timestamp, error) {
+	if s == "" {
+		return Timestamp{}, nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Timestamp{Time: t, layout: layout}, nil
+		}
+	}
+
+	return Timestamp{}, fmt.Errorf("opendataug: cannot parse timestamp %q", s)
+}
+
+// IsZero reports whether the timestamp is missing. A timestamp whose text
+// could not be parsed is not missing, but its Time is zero.
+func (t Timestamp) IsZero() bool {
+	return t.Time.IsZero() && t.raw == ""
+}
+
+// Raw returns the original text of a timestamp that could not be parsed, and
+// an empty string otherwise
+func (t Timestamp) Raw() string {
+	return t.raw
+}
+
+// String returns the timestamp in the layout it was parsed from, or the
+// original text if it could not be parsed
+func (t Timestamp) String() string {
+	if t.Time.IsZero() {
+		return t.raw
+	}
+	if t.layout == "" {
+		return t.Time.Format(time.RFC3339Nano)
+	}
+	return t.Time.Format(t.layout)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Text in an unknown
+// format yields a zero time that keeps the text, rather than an error.
+func (t *Timestamp) UnmarshalText(data []byte) error {
+	parsed, err := ParseTimestamp(string(data))
+	if err != nil {
+		*t = Timestamp{raw: string(data)}
+		return nil
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The zero Timestamp marshals as null.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = Timestamp{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return t.UnmarshalText([]byte(s))
+}
+
+// Scan implements sql.Scanner
+func (t *Timestamp) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = Timestamp{}
+		return nil
+	case time.Time:
+		*t = Timestamp{Time: v}
+		return nil
+	case string:
+		return t.UnmarshalText([]byte(v))
+	case []byte:
+		return t.UnmarshalText(v)
+	}
+	return fmt.Errorf("opendataug: cannot scan %T into Timestamp", src)
+}
+
+// Value implements driver.Valuer. Timestamps without a usable time are stored
+// as NULL.
+func (t Timestamp) Value() (driver.Value, error) {
+	if t.Time.IsZero() {
+		return nil, nil
+	}
+	return t.Time, nil
+}
@@ -0,0 +1,197 @@
+package opendataug
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedTime time.Time
+		expectedRaw  string
+		expectError  bool
+	}{
+		{
+			name:         "RFC 3339",
+			input:        `"2024-03-02T10:30:00Z"`,
+			expectedTime: time.Date(2024, 3, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "RFC 3339 with offset",
+			input:        `"2024-03-02T13:30:00+03:00"`,
+			expectedTime: time.Date(2024, 3, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "Fractional seconds",
+			input:        `"2024-03-02T10:30:00.123456Z"`,
+			expectedTime: time.Date(2024, 3, 2, 10, 30, 0, 123456000, time.UTC),
+		},
+		{
+			name:         "Without zone",
+			input:        `"2024-03-02 10:30:00"`,
+			expectedTime: time.Date(2024, 3, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "Date only",
+			input:        `"2024-03-02"`,
+			expectedTime: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Empty",
+			input: `""`,
+		},
+		{
+			name:  "Null",
+			input: `null`,
+		},
+		{
+			name:        "Unknown format",
+			input:       `"02/03/2024"`,
+			expectedRaw: "02/03/2024",
+		},
+		{
+			name:        "Not a string",
+			input:       `42`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var ts Timestamp
+			err := json.Unmarshal([]byte(tc.input), &ts)
+
+			if tc.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if !ts.Time.Equal(tc.expectedTime) {
+				t.Errorf("Expected %v, got %v", tc.expectedTime, ts.Time)
+			}
+
+			if ts.Raw() != tc.expectedRaw {
+				t.Errorf("Expected raw text %q, got %q", tc.expectedRaw, ts.Raw())
+			}
+		})
+	}
+}
+
+func TestTimestampRoundTrip(t *testing.T) {
+	inputs := []string{
+		`{"id":"county-1","name":"Nakawa","code":"NKW","district_id":"district-1","created_at":"2023-01-01","updated_at":"2024-03-02T13:30:00+03:00"}`,
+		`{"id":"county-1","name":"Nakawa","code":"NKW","district_id":"district-1","updated_at":"2024-03-02 10:30:00"}`,
+		`{"id":"county-1","name":"Nakawa","code":"NKW","district_id":"district-1"}`,
+	}
+
+	for _, input := range inputs {
+		var county County
+		if err := json.Unmarshal([]byte(input), &county); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		output, err := json.Marshal(county)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if string(output) != input {
+			t.Errorf("Expected %s, got %s", input, output)
+		}
+	}
+}
+
+func TestTimestampSQL(t *testing.T) {
+	var ts Timestamp
+
+	if err := ts.Scan("2024-03-02"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	value, err := ts.Value()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	if v, ok := value.(time.Time); !ok || !v.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, value)
+	}
+
+	if err := ts.Scan(nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	value, err = ts.Value()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if value != nil {
+		t.Errorf("Expected nil value for a zero timestamp, got %v", value)
+	}
+
+	if err := ts.Scan(42); err == nil {
+		t.Error("Expected an error scanning an int, got nil")
+	}
+}
+
+func TestTimestampUnknownFormatInList(t *testing.T) {
+	server, client := TestServer(t, "/villages", `{
+		"data": [
+			{"id": "village-1", "name": "Kiwatule A", "parish_id": "parish-1", "updated_at": "2024-03-02"},
+			{"id": "village-2", "name": "Kiwatule B", "parish_id": "parish-1", "updated_at": "02/03/2024"}
+		]
+	}`)
+	defer server.Close()
+
+	villages, err := client.GetVillages()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(villages) != 2 {
+		t.Fatalf("Expected 2 villages, got %+v", villages)
+	}
+
+	if !villages[1].UpdatedAt.Time.IsZero() || villages[1].UpdatedAt.String() != "02/03/2024" {
+		t.Errorf("Expected a zero time keeping the raw text, got %+v", villages[1].UpdatedAt)
+	}
+
+	data, err := json.Marshal(villages[1].UpdatedAt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != `"02/03/2024"` {
+		t.Errorf("Expected the raw text to marshal back, got %s", data)
+	}
+}
+
+func TestTimestampUnknownFormatUpdatedSince(t *testing.T) {
+	server, client := TestServer(t, "/villages", `{
+		"data": [
+			{"id": "village-1", "parish_id": "parish-1", "updated_at": "2023-01-01"},
+			{"id": "village-2", "parish_id": "parish-1", "updated_at": "02/03/2024"}
+		]
+	}`)
+	defer server.Close()
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	villages, mark, err := client.GetVillagesUpdatedSince(context.Background(), since)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The unparsed timestamp cannot show the village is unchanged
+	if len(villages) != 1 || villages[0].ID != "village-2" {
+		t.Errorf("Expected only village-2, got %+v", villages)
+	}
+	if !mark.Equal(since) {
+		t.Errorf("Expected the high-water mark to stay at %v, got %v", since, mark)
+	}
+}
@@ -0,0 +1,362 @@
+package opendataug
+
+import "context"
+
+// AdminTree is an in-memory copy of the whole administrative hierarchy with
+// links between parents and children. It is immutable once built, so it is
+// safe for concurrent use, and every accessor returns copies.
+//
+// Units whose parent is missing from the tree can still be looked up by ID
+// but are not listed among the children of any unit.
+type AdminTree struct {
+	districts   []District
+	counties    []County
+	subcounties []Subcounty
+	parishes    []Parish
+	villages    []Village
+
+	districtIndex  map[DistrictID]int
+	countyIndex    map[CountyID]int
+	subcountyIndex map[SubcountyID]int
+	parishIndex    map[ParishID]int
+	villageIndex   map[VillageID]int
+
+	countiesOf    map[DistrictID][]int
+	subcountiesOf map[CountyID][]int
+	parishesOf    map[SubcountyID][]int
+	villagesOf    map[ParishID][]int
+
+	districtCounts  map[DistrictID]Counts
+	countyCounts    map[CountyID]Counts
+	subcountyCounts map[SubcountyID]Counts
+}
+
+// Counts holds the number of units at each level
+type Counts struct {
+	Districts   int `json:"districts"`
+	Counties    int `json:"counties"`
+	Subcounties int `json:"subcounties"`
+	Parishes    int `json:"parishes"`
+	Villages    int `json:"villages"`
+}
+
+func (c Counts) add(o Counts) Counts {
+	return Counts{
+		Districts:   c.Districts + o.Districts,
+		Counties:    c.Counties + o.Counties,
+		Subcounties: c.Subcounties + o.Subcounties,
+		Parishes:    c.Parishes + o.Parishes,
+		Villages:    c.Villages + o.Villages,
+	}
+}
+
+// BuildTree loads all five levels of the hierarchy concurrently and links
+// them into an AdminTree
+func BuildTree(ctx context.Context, api API) (*AdminTree, error) {
+	ds, err := LoadDataset(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAdminTree(ds), nil
+}
+
+// NewAdminTree links the units in ds into an AdminTree. The tree keeps its own
+// copy of the units, so ds may be modified afterwards. When an ID occurs more
+// than once only its first unit is kept.
+func NewAdminTree(ds *Dataset) *AdminTree {
+	t := &AdminTree{
+		districtIndex:   make(map[DistrictID]int, len(ds.Districts)),
+		countyIndex:     make(map[CountyID]int, len(ds.Counties)),
+		subcountyIndex:  make(map[SubcountyID]int, len(ds.Subcounties)),
+		parishIndex:     make(map[ParishID]int, len(ds.Parishes)),
+		villageIndex:    make(map[VillageID]int, len(ds.Villages)),
+		countiesOf:      make(map[DistrictID][]int),
+		subcountiesOf:   make(map[CountyID][]int),
+		parishesOf:      make(map[SubcountyID][]int),
+		villagesOf:      make(map[ParishID][]int),
+		districtCounts:  make(map[DistrictID]Counts, len(ds.Districts)),
+		countyCounts:    make(map[CountyID]Counts, len(ds.Counties)),
+		subcountyCounts: make(map[SubcountyID]Counts, len(ds.Subcounties)),
+	}
+
+	for _, district := range ds.Districts {
+		if _, ok := t.districtIndex[district.ID]; ok {
+			continue
+		}
+		t.districtIndex[district.ID] = len(t.districts)
+		t.districts = append(t.districts, district)
+	}
+	for _, county := range ds.Counties {
+		if _, ok := t.countyIndex[county.ID]; ok {
+			continue
+		}
+		t.countyIndex[county.ID] = len(t.counties)
+		t.counties = append(t.counties, county)
+	}
+	for _, subcounty := range ds.Subcounties {
+		if _, ok := t.subcountyIndex[subcounty.ID]; ok {
+			continue
+		}
+		t.subcountyIndex[subcounty.ID] = len(t.subcounties)
+		t.subcounties = append(t.subcounties, subcounty)
+	}
+	for _, parish := range ds.Parishes {
+		if _, ok := t.parishIndex[parish.ID]; ok {
+			continue
+		}
+		t.parishIndex[parish.ID] = len(t.parishes)
+		t.parishes = append(t.parishes, parish)
+	}
+	for _, village := range ds.Villages {
+		if _, ok := t.villageIndex[village.ID]; ok {
+			continue
+		}
+		t.villageIndex[village.ID] = len(t.villages)
+		t.villages = append(t.villages, village)
+	}
+
+	for i, county := range t.counties {
+		if _, ok := t.districtIndex[county.DistrictID]; ok {
+			t.countiesOf[county.DistrictID] = append(t.countiesOf[county.DistrictID], i)
+		}
+	}
+	for i, subcounty := range t.subcounties {
+		if _, ok := t.countyIndex[subcounty.CountyID]; ok {
+			t.subcountiesOf[subcounty.CountyID] = append(t.subcountiesOf[subcounty.CountyID], i)
+		}
+	}
+	for i, parish := range t.parishes {
+		if _, ok := t.subcountyIndex[parish.SubcountyID]; ok {
+			t.parishesOf[parish.SubcountyID] = append(t.parishesOf[parish.SubcountyID], i)
+		}
+	}
+	for i, village := range t.villages {
+		if _, ok := t.parishIndex[village.ParishID]; ok {
+			t.villagesOf[village.ParishID] = append(t.villagesOf[village.ParishID], i)
+		}
+	}
+
+	for _, subcounty := range t.subcounties {
+		counts := Counts{Parishes: len(t.parishesOf[subcounty.ID])}
+		for _, i := range t.parishesOf[subcounty.ID] {
+			counts.Villages += len(t.villagesOf[t.parishes[i].ID])
+		}
+		t.subcountyCounts[subcounty.ID] = counts
+	}
+	for _, county := range t.counties {
+		counts := Counts{Subcounties: len(t.subcountiesOf[county.ID])}
+		for _, i := range t.subcountiesOf[county.ID] {
+			counts = counts.add(t.subcountyCounts[t.subcounties[i].ID])
+		}
+		t.countyCounts[county.ID] = counts
+	}
+	for _, district := range t.districts {
+		counts := Counts{Counties: len(t.countiesOf[district.ID])}
+		for _, i := range t.countiesOf[district.ID] {
+			counts = counts.add(t.countyCounts[t.counties[i].ID])
+		}
+		t.districtCounts[district.ID] = counts
+	}
+
+	return t
+}
+
+// Counts returns the number of units at each level of the tree
+func (t *AdminTree) Counts() Counts {
+	return Counts{
+		Districts:   len(t.districts),
+		Counties:    len(t.counties),
+		Subcounties: len(t.subcounties),
+		Parishes:    len(t.parishes),
+		Villages:    len(t.villages),
+	}
+}
+
+// Districts returns every district in the tree
+func (t *AdminTree) Districts() []District {
+	return append([]District(nil), t.districts...)
+}
+
+// Counties returns every county in the tree
+func (t *AdminTree) Counties() []County {
+	return append([]County(nil), t.counties...)
+}
+
+// Subcounties returns every subcounty in the tree
+func (t *AdminTree) Subcounties() []Subcounty {
+	return append([]Subcounty(nil), t.subcounties...)
+}
+
+// Parishes returns every parish in the tree
+func (t *AdminTree) Parishes() []Parish {
+	return append([]Parish(nil), t.parishes...)
+}
+
+// Villages returns every village in the tree
+func (t *AdminTree) Villages() []Village {
+	return append([]Village(nil), t.villages...)
+}
+
+// District returns the district with the given ID
+func (t *AdminTree) District(id DistrictID) (District, bool) {
+	i, ok := t.districtIndex[id]
+	if !ok {
+		return District{}, false
+	}
+	return t.districts[i], true
+}
+
+// County returns the county with the given ID
+func (t *AdminTree) County(id CountyID) (County, bool) {
+	i, ok := t.countyIndex[id]
+	if !ok {
+		return County{}, false
+	}
+	return t.counties[i], true
+}
+
+// Subcounty returns the subcounty with the given ID
+func (t *AdminTree) Subcounty(id SubcountyID) (Subcounty, bool) {
+	i, ok := t.subcountyIndex[id]
+	if !ok {
+		return Subcounty{}, false
+	}
+	return t.subcounties[i], true
+}
+
+// Parish returns the parish with the given ID
+func (t *AdminTree) Parish(id ParishID) (Parish, bool) {
+	i, ok := t.parishIndex[id]
+	if !ok {
+		return Parish{}, false
+	}
+	return t.parishes[i], true
+}
+
+// Village returns the village with the given ID
+func (t *AdminTree) Village(id VillageID) (Village, bool) {
+	i, ok := t.villageIndex[id]
+	if !ok {
+		return Village{}, false
+	}
+	return t.villages[i], true
+}
+
+// CountiesOf returns the counties in a specific district
+func (t *AdminTree) CountiesOf(id DistrictID) []County {
+	return pick(t.counties, t.countiesOf[id])
+}
+
+// SubcountiesOf returns the subcounties in a specific county
+func (t *AdminTree) SubcountiesOf(id CountyID) []Subcounty {
+	return pick(t.subcounties, t.subcountiesOf[id])
+}
+
+// ParishesOf returns the parishes in a specific subcounty
+func (t *AdminTree) ParishesOf(id SubcountyID) []Parish {
+	return pick(t.parishes, t.parishesOf[id])
+}
+
+// VillagesOf returns the villages in a specific parish
+func (t *AdminTree) VillagesOf(id ParishID) []Village {
+	return pick(t.villages, t.villagesOf[id])
+}
+
+// DistrictOf returns the district a specific county belongs to
+func (t *AdminTree) DistrictOf(id CountyID) (District, bool) {
+	county, ok := t.County(id)
+	if !ok {
+		return District{}, false
+	}
+	return t.District(county.DistrictID)
+}
+
+// CountyOf returns the county a specific subcounty belongs to
+func (t *AdminTree) CountyOf(id SubcountyID) (County, bool) {
+	subcounty, ok := t.Subcounty(id)
+	if !ok {
+		return County{}, false
+	}
+	return t.County(subcounty.CountyID)
+}
+
+// SubcountyOf returns the subcounty a specific parish belongs to
+func (t *AdminTree) SubcountyOf(id ParishID) (Subcounty, bool) {
+	parish, ok := t.Parish(id)
+	if !ok {
+		return Subcounty{}, false
+	}
+	return t.Subcounty(parish.SubcountyID)
+}
+
+// ParishOf returns the parish a specific village belongs to
+func (t *AdminTree) ParishOf(id VillageID) (Parish, bool) {
+	village, ok := t.Village(id)
+	if !ok {
+		return Parish{}, false
+	}
+	return t.Parish(village.ParishID)
+}
+
+// DistrictCounts returns the number of units at each level below a specific
+// district
+func (t *AdminTree) DistrictCounts(id DistrictID) Counts {
+	return t.districtCounts[id]
+}
+
+// CountyCounts returns the number of units at each level below a specific
+// county
+func (t *AdminTree) CountyCounts(id CountyID) Counts {
+	return t.countyCounts[id]
+}
+
+// SubcountyCounts returns the number of units at each level below a specific
+// subcounty
+func (t *AdminTree) SubcountyCounts(id SubcountyID) Counts {
+	return t.subcountyCounts[id]
+}
+
+// ParishCounts returns the number of villages in a specific parish
+func (t *AdminTree) ParishCounts(id ParishID) Counts {
+	return Counts{Villages: len(t.villagesOf[id])}
+}
+
+func pick[T any](items []T, indexes []int) []T {
+	picked := make([]T, len(indexes))
+	for i, index := range indexes {
+		picked[i] = items[index]
+	}
+	return picked
+}
+
+// Path returns the path from the region down to u. It fails if u or one of
+// its ancestors is missing from the tree.
+func (t *AdminTree) Path(u AdminUnit) (*AdminPath, bool) {
+	u, ok := t.Unit(u.Level(), u.UnitID())
+	if !ok {
+		return nil, false
+	}
+
+	path := &AdminPath{}
+	for {
+		switch v := u.(type) {
+		case District:
+			path.District = &v
+			path.Region = Region{ID: v.RegionID, Name: v.RegionName}
+			return path, true
+		case County:
+			path.County = &v
+		case Subcounty:
+			path.Subcounty = &v
+		case Parish:
+			path.Parish = &v
+		case Village:
+			path.Village = &v
+		}
+
+		if u, ok = t.Parent(u); !ok {
+			return nil, false
+		}
+	}
+}
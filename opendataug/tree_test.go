@@ -0,0 +1,234 @@
+package opendataug
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// testDataset returns a small hierarchy covering two districts. Village
+// village-9 is an orphan whose parish does not exist.
+func testDataset() *Dataset {
+	return &Dataset{
+		Districts: []District{
+			{ID: "district-1", Name: "Kampala", TownStatus: true, RegionID: "region-1", RegionName: "Central"},
+			{ID: "district-2", Name: "Wakiso", RegionID: "region-1", RegionName: "Central"},
+		},
+		Counties: []County{
+			{ID: "county-1", Name: "Nakawa", Code: "NKW", DistrictID: "district-1"},
+			{ID: "county-2", Name: "Kawempe", Code: "KWP", DistrictID: "district-1"},
+			{ID: "county-3", Name: "Kyadondo", Code: "KYD", DistrictID: "district-2"},
+		},
+		Subcounties: []Subcounty{
+			{ID: "subcounty-1", Name: "Nakawa Division", Code: "NKW-D", CountyID: "county-1"},
+			{ID: "subcounty-2", Name: "Kawempe Division", Code: "KWP-D", CountyID: "county-2"},
+			{ID: "subcounty-3", Name: "Nangabo", Code: "NGB", CountyID: "county-3"},
+		},
+		Parishes: []Parish{
+			{ID: "parish-1", Name: "Kiwatule", Code: "KWT", SubcountyID: "subcounty-1"},
+			{ID: "parish-2", Name: "Ntinda", Code: "NTD", SubcountyID: "subcounty-1"},
+			{ID: "parish-3", Name: "Kazo", Code: "KZO", SubcountyID: "subcounty-2"},
+			{ID: "parish-4", Name: "Gayaza", Code: "GYZ", SubcountyID: "subcounty-3"},
+		},
+		Villages: []Village{
+			{ID: "village-1", Name: "Kiwatule A", Code: "001", ParishID: "parish-1"},
+			{ID: "village-2", Name: "Kiwatule B", Code: "002", ParishID: "parish-1"},
+			{ID: "village-3", Name: "Ntinda Central", Code: "001", ParishID: "parish-2"},
+			{ID: "village-4", Name: "Kazo Central", Code: "001", ParishID: "parish-3"},
+			{ID: "village-5", Name: "Gayaza Trading Centre", Code: "001", ParishID: "parish-4"},
+			{ID: "village-9", Name: "Lost Village", Code: "009", ParishID: "parish-missing"},
+		},
+	}
+}
+
+// datasetRoutes returns routes for TestRoutesServer serving every level of ds
+// from its list endpoint
+func datasetRoutes(t *testing.T, ds *Dataset) map[string]string {
+	routes := make(map[string]string)
+	add := func(path string, data interface{}) {
+		body, err := json.Marshal(map[string]interface{}{"data": data})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		routes[path] = string(body)
+	}
+
+	add("/districts", ds.Districts)
+	add("/counties", ds.Counties)
+	add("/subcounties", ds.Subcounties)
+	add("/parishes", ds.Parishes)
+	add("/villages", ds.Villages)
+
+	return routes
+}
+
+func TestBuildTree(t *testing.T) {
+	server, client := TestRoutesServer(t, datasetRoutes(t, testDataset()))
+	defer server.Close()
+
+	tree, err := BuildTree(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := Counts{Districts: 2, Counties: 3, Subcounties: 3, Parishes: 4, Villages: 6}
+	if tree.Counts() != expected {
+		t.Errorf("Expected %+v, got %+v", expected, tree.Counts())
+	}
+}
+
+func TestBuildTreeError(t *testing.T) {
+	routes := datasetRoutes(t, testDataset())
+	delete(routes, "/parishes")
+
+	server, client := TestRoutesServer(t, routes)
+	defer server.Close()
+
+	if _, err := BuildTree(context.Background(), client); err == nil {
+		t.Error("Expected an error, got nil")
+	}
+}
+
+func TestAdminTreeLookups(t *testing.T) {
+	tree := NewAdminTree(testDataset())
+
+	district, ok := tree.District("district-1")
+	if !ok || district.Name != "Kampala" {
+		t.Errorf("Expected Kampala, got %+v", district)
+	}
+
+	if _, ok := tree.County("county-missing"); ok {
+		t.Error("Expected missing county not to be found")
+	}
+
+	village, ok := tree.Village("village-9")
+	if !ok || village.Name != "Lost Village" {
+		t.Errorf("Expected orphan village to be found by ID, got %+v", village)
+	}
+
+	var counties []CountyID
+	for _, county := range tree.CountiesOf("district-1") {
+		counties = append(counties, county.ID)
+	}
+	if !reflect.DeepEqual(counties, []CountyID{"county-1", "county-2"}) {
+		t.Errorf("Expected [county-1 county-2], got %v", counties)
+	}
+
+	if villages := tree.VillagesOf("parish-missing"); len(villages) != 0 {
+		t.Errorf("Expected orphans not to be linked, got %+v", villages)
+	}
+
+	parish, ok := tree.ParishOf("village-3")
+	if !ok || parish.ID != "parish-2" {
+		t.Errorf("Expected parish-2, got %+v", parish)
+	}
+
+	if _, ok := tree.ParishOf("village-9"); ok {
+		t.Error("Expected orphan village to have no parish")
+	}
+
+	parentDistrict, ok := tree.DistrictOf("county-3")
+	if !ok || parentDistrict.ID != "district-2" {
+		t.Errorf("Expected district-2, got %+v", parentDistrict)
+	}
+}
+
+func TestAdminTreeCounts(t *testing.T) {
+	tree := NewAdminTree(testDataset())
+
+	tests := []struct {
+		name     string
+		counts   Counts
+		expected Counts
+	}{
+		{
+			name:     "District",
+			counts:   tree.DistrictCounts("district-1"),
+			expected: Counts{Counties: 2, Subcounties: 2, Parishes: 3, Villages: 4},
+		},
+		{
+			name:     "County",
+			counts:   tree.CountyCounts("county-3"),
+			expected: Counts{Subcounties: 1, Parishes: 1, Villages: 1},
+		},
+		{
+			name:     "Subcounty",
+			counts:   tree.SubcountyCounts("subcounty-1"),
+			expected: Counts{Parishes: 2, Villages: 3},
+		},
+		{
+			name:     "Parish",
+			counts:   tree.ParishCounts("parish-1"),
+			expected: Counts{Villages: 2},
+		},
+		{
+			name:     "Unknown",
+			counts:   tree.DistrictCounts("district-missing"),
+			expected: Counts{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.counts != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, tc.counts)
+			}
+		})
+	}
+}
+
+func TestAdminTreeIsImmutable(t *testing.T) {
+	ds := testDataset()
+	tree := NewAdminTree(ds)
+
+	ds.Districts[0].Name = "Changed"
+	tree.Districts()[0].Name = "Changed"
+	tree.CountiesOf("district-1")[0].Name = "Changed"
+
+	district, _ := tree.District("district-1")
+	if district.Name != "Kampala" {
+		t.Errorf("Expected Kampala, got %s", district.Name)
+	}
+
+	county, _ := tree.County("county-1")
+	if county.Name != "Nakawa" {
+		t.Errorf("Expected Nakawa, got %s", county.Name)
+	}
+}
+
+func TestAdminTreeDuplicateIDs(t *testing.T) {
+	ds := testDataset()
+	ds.Districts = append(ds.Districts, District{ID: "district-1", Name: "Duplicate"})
+
+	tree := NewAdminTree(ds)
+
+	district, _ := tree.District("district-1")
+	if district.Name != "Kampala" {
+		t.Errorf("Expected the first district to be kept, got %s", district.Name)
+	}
+	if len(tree.Districts()) != 2 {
+		t.Errorf("Expected 2 districts, got %d", len(tree.Districts()))
+	}
+}
+
+func TestAdminTreePath(t *testing.T) {
+	tree := NewAdminTree(testDataset())
+
+	village, _ := tree.Village("village-4")
+	path, ok := tree.Path(&village)
+	if !ok {
+		t.Fatal("Expected a path")
+	}
+	if path.Parish.ID != "parish-3" || path.Subcounty.ID != "subcounty-2" || path.County.ID != "county-2" || path.District.ID != "district-1" {
+		t.Errorf("Unexpected path %+v", path)
+	}
+	if path.Region.Name != "Central" {
+		t.Errorf("Expected region Central, got %s", path.Region.Name)
+	}
+
+	orphan, _ := tree.Village("village-9")
+	if _, ok := tree.Path(orphan); ok {
+		t.Error("Expected no path for an orphan")
+	}
+}
@@ -0,0 +1,82 @@
+package opendataug
+
+// AdminUnit is implemented by the five models of the hierarchy so that generic
+// code does not need to switch on their types. The methods are prefixed with
+// Unit where the plain name is taken by a field of the models.
+type AdminUnit interface {
+	// UnitID returns the ID of the unit
+	UnitID() string
+	// UnitName returns the name of the unit
+	UnitName() string
+	// UnitCode returns the administrative code of the unit, which is empty
+	// for districts
+	UnitCode() string
+	// Level returns the level of the unit
+	Level() Level
+	// ParentID returns the ID of the unit one level up, which is empty for
+	// districts
+	ParentID() string
+}
+
+var (
+	_ AdminUnit = District{}
+	_ AdminUnit = County{}
+	_ AdminUnit = Subcounty{}
+	_ AdminUnit = Parish{}
+	_ AdminUnit = Village{}
+)
+
+func (d District) UnitID() string   { return string(d.ID) }
+func (d District) UnitName() string { return d.Name }
+func (d District) UnitCode() string { return "" }
+func (d District) Level() Level     { return LevelDistrict }
+func (d District) ParentID() string { return "" }
+
+func (c County) UnitID() string   { return string(c.ID) }
+func (c County) UnitName() string { return c.Name }
+func (c County) UnitCode() string { return c.Code }
+func (c County) Level() Level     { return LevelCounty }
+func (c County) ParentID() string { return string(c.DistrictID) }
+
+func (s Subcounty) UnitID() string   { return string(s.ID) }
+func (s Subcounty) UnitName() string { return s.Name }
+func (s Subcounty) UnitCode() string { return s.Code }
+func (s Subcounty) Level() Level     { return LevelSubcounty }
+func (s Subcounty) ParentID() string { return string(s.CountyID) }
+
+func (p Parish) UnitID() string   { return string(p.ID) }
+func (p Parish) UnitName() string { return p.Name }
+func (p Parish) UnitCode() string { return p.Code }
+func (p Parish) Level() Level     { return LevelParish }
+func (p Parish) ParentID() string { return string(p.SubcountyID) }
+
+func (v Village) UnitID() string   { return string(v.ID) }
+func (v Village) UnitName() string { return v.Name }
+func (v Village) UnitCode() string { return v.Code }
+func (v Village) Level() Level     { return LevelVillage }
+func (v Village) ParentID() string { return string(v.ParishID) }
+
+// UnitKey identifies a unit of any level. IDs are only unique within a level,
+// so the level is part of the key.
+type UnitKey struct {
+	Level Level  `json:"level"`
+	ID    string `json:"id"`
+}
+
+// KeyOf returns the key of u
+func KeyOf(u AdminUnit) UnitKey {
+	return UnitKey{Level: u.Level(), ID: u.UnitID()}
+}
+
+// String returns the key as level/id
+func (k UnitKey) String() string {
+	return k.Level.String() + "/" + k.ID
+}
+
+func units[T AdminUnit](items []T) []AdminUnit {
+	converted := make([]AdminUnit, len(items))
+	for i, item := range items {
+		converted[i] = item
+	}
+	return converted
+}
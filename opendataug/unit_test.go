@@ -0,0 +1,60 @@
+package opendataug
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdminUnit(t *testing.T) {
+	ds := testDataset()
+
+	tests := []struct {
+		name     string
+		unit     AdminUnit
+		expected []string
+		level    Level
+	}{
+		{
+			name:     "District",
+			unit:     ds.Districts[0],
+			expected: []string{"district-1", "Kampala", "", ""},
+			level:    LevelDistrict,
+		},
+		{
+			name:     "County",
+			unit:     ds.Counties[0],
+			expected: []string{"county-1", "Nakawa", "NKW", "district-1"},
+			level:    LevelCounty,
+		},
+		{
+			name:     "Subcounty",
+			unit:     &ds.Subcounties[0],
+			expected: []string{"subcounty-1", "Nakawa Division", "NKW-D", "county-1"},
+			level:    LevelSubcounty,
+		},
+		{
+			name:     "Parish",
+			unit:     ds.Parishes[0],
+			expected: []string{"parish-1", "Kiwatule", "KWT", "subcounty-1"},
+			level:    LevelParish,
+		},
+		{
+			name:     "Village",
+			unit:     ds.Villages[0],
+			expected: []string{"village-1", "Kiwatule A", "001", "parish-1"},
+			level:    LevelVillage,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := []string{tc.unit.UnitID(), tc.unit.UnitName(), tc.unit.UnitCode(), tc.unit.ParentID()}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+			if tc.unit.Level() != tc.level {
+				t.Errorf("Expected level %v, got %v", tc.level, tc.unit.Level())
+			}
+		})
+	}
+}
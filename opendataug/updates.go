@@ -0,0 +1,85 @@
+package opendataug
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// The methods in this file support incremental synchronisation. Each sends
+// the timestamp to the server as the updated_since parameter and filters the
+// response on the client as well, so servers that ignore the parameter only
+// cost bandwidth. Records without an updated_at, or with one that could not
+// be parsed, are always returned, since they cannot be shown to be unchanged.
+//
+// The returned high-water mark is the latest updated_at seen, or since when no
+// newer record was returned, and should be passed as since on the next call.
+// The comparison is inclusive: records updated exactly at since are returned
+// again, so that a record written in the same instant as the last one seen
+// is not missed. Callers should merge results by ID.
+
+// GetCountiesUpdatedSince retrieves all counties updated at or after since
+func (c *Client) GetCountiesUpdatedSince(ctx context.Context, since time.Time) ([]County, time.Time, error) {
+	return updatedSince(ctx, c, "/counties", since, func(county County) Timestamp {
+		return county.UpdatedAt
+	})
+}
+
+// GetSubcountiesUpdatedSince retrieves all subcounties updated at or after since
+func (c *Client) GetSubcountiesUpdatedSince(ctx context.Context, since time.Time) ([]Subcounty, time.Time, error) {
+	return updatedSince(ctx, c, "/subcounties", since, func(subcounty Subcounty) Timestamp {
+		return subcounty.UpdatedAt
+	})
+}
+
+// GetParishesUpdatedSince retrieves all parishes updated at or after since
+func (c *Client) GetParishesUpdatedSince(ctx context.Context, since time.Time) ([]Parish, time.Time, error) {
+	return updatedSince(ctx, c, "/parishes", since, func(parish Parish) Timestamp {
+		return parish.UpdatedAt
+	})
+}
+
+// GetVillagesUpdatedSince retrieves all villages updated at or after since
+func (c *Client) GetVillagesUpdatedSince(ctx context.Context, since time.Time) ([]Village, time.Time, error) {
+	return updatedSince(ctx, c, "/villages", since, func(village Village) Timestamp {
+		return village.UpdatedAt
+	})
+}
+
+func updatedSince[T any](ctx context.Context, c *Client, path string, since time.Time, updatedAt func(T) Timestamp) ([]T, time.Time, error) {
+	if !since.IsZero() {
+		query := url.Values{"updated_since": {since.UTC().Format(time.RFC3339Nano)}}
+		path += "?" + query.Encode()
+	}
+
+	items, err := getList[T](ctx, c, path)
+	if err != nil {
+		return nil, since, err
+	}
+
+	changed, mark := filterUpdatedSince(items, since, updatedAt)
+	return changed, mark, nil
+}
+
+// filterUpdatedSince returns the items updated at or after since, or without
+// an updated_at, along with the high-water mark
+func filterUpdatedSince[T any](items []T, since time.Time, updatedAt func(T) Timestamp) ([]T, time.Time) {
+	mark := since
+	changed := make([]T, 0, len(items))
+	for _, item := range items {
+		updated := updatedAt(item)
+		if updated.Time.IsZero() {
+			changed = append(changed, item)
+			continue
+		}
+		if updated.Before(since) {
+			continue
+		}
+		changed = append(changed, item)
+		if updated.After(mark) {
+			mark = updated.Time
+		}
+	}
+
+	return changed, mark
+}
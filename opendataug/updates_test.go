@@ -0,0 +1,121 @@
+package opendataug
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetVillagesUpdatedSince(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/villages" {
+			t.Errorf("Expected request to /villages, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("updated_since") != "2024-03-01T00:00:00Z" {
+			t.Errorf("Expected updated_since to be 2024-03-01T00:00:00Z, got %s", r.URL.Query().Get("updated_since"))
+		}
+
+		// The server ignores updated_since, so older records come back too.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"data": [
+				{"id": "village-1", "name": "Kiwatule A", "parish_id": "parish-1", "updated_at": "2024-01-15T08:00:00Z"},
+				{"id": "village-2", "name": "Kiwatule B", "parish_id": "parish-1", "updated_at": "2024-03-02T10:30:00Z"},
+				{"id": "village-3", "name": "Kazo Central", "parish_id": "parish-2", "updated_at": "2024-03-05"},
+				{"id": "village-4", "name": "Kazo Angola", "parish_id": "parish-2"},
+				{"id": "village-5", "name": "Ntinda", "parish_id": "parish-3", "updated_at": "2024-03-01T00:00:00Z"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	originalBaseURL := baseURL
+	baseURL = server.URL
+	defer func() { baseURL = originalBaseURL }()
+
+	client := NewClient("test-api-key")
+
+	villages, mark, err := client.GetVillagesUpdatedSince(context.Background(), since)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var ids []VillageID
+	for _, village := range villages {
+		ids = append(ids, village.ID)
+	}
+
+	// village-5 was updated exactly at since, which is included
+	expectedIDs := []VillageID{"village-2", "village-3", "village-4", "village-5"}
+	if !reflect.DeepEqual(ids, expectedIDs) {
+		t.Errorf("Expected %v, got %v", expectedIDs, ids)
+	}
+
+	expectedMark := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	if !mark.Equal(expectedMark) {
+		t.Errorf("Expected high-water mark %v, got %v", expectedMark, mark)
+	}
+}
+
+func TestGetCountiesUpdatedSinceNoChanges(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	server, client := TestServer(t, "/counties", `{"data": []}`)
+	defer server.Close()
+
+	counties, mark, err := client.GetCountiesUpdatedSince(context.Background(), since)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(counties) != 0 {
+		t.Errorf("Expected no counties, got %+v", counties)
+	}
+
+	if !mark.Equal(since) {
+		t.Errorf("Expected high-water mark %v, got %v", since, mark)
+	}
+}
+
+func TestGetParishesUpdatedSinceZero(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "" {
+			t.Errorf("Expected no query for a full fetch, got %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"data": [
+				{"id": "parish-1", "name": "Kiwatule", "subcounty_id": "subcounty-1", "updated_at": "2023-06-01T12:00:00Z"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	originalBaseURL := baseURL
+	baseURL = server.URL
+	defer func() { baseURL = originalBaseURL }()
+
+	client := NewClient("test-api-key")
+
+	parishes, mark, err := client.GetParishesUpdatedSince(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(parishes) != 1 {
+		t.Errorf("Expected 1 parish, got %d", len(parishes))
+	}
+
+	expectedMark := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	if !mark.Equal(expectedMark) {
+		t.Errorf("Expected high-water mark %v, got %v", expectedMark, mark)
+	}
+}
@@ -0,0 +1,221 @@
+package opendataug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// IssueKind classifies a data-quality issue
+type IssueKind string
+
+const (
+	// IssueEmptyField is a required field that is blank
+	IssueEmptyField IssueKind = "empty_field"
+	// IssueMissingParent is a unit below district level with a blank
+	// parent ID
+	IssueMissingParent IssueKind = "missing_parent"
+	// IssueDanglingParent is a parent ID that points to no unit
+	IssueDanglingParent IssueKind = "dangling_parent"
+	// IssueOrphan is a unit whose parent exists but cannot be traced up to
+	// a district
+	IssueOrphan IssueKind = "orphan"
+	// IssueDuplicateID is an ID used by more than one unit of a level
+	IssueDuplicateID IssueKind = "duplicate_id"
+	// IssueDuplicateCode is a code used by more than one child of the same
+	// parent
+	IssueDuplicateCode IssueKind = "duplicate_code"
+)
+
+// Issue is a single problem found by the validator
+type Issue struct {
+	Kind    IssueKind `json:"kind"`
+	Level   Level     `json:"level"`
+	ID      string    `json:"id"`
+	Field   string    `json:"field,omitempty"`
+	Value   string    `json:"value,omitempty"`
+	Message string    `json:"message"`
+}
+
+// ValidationReport lists the issues found in a dataset
+type ValidationReport struct {
+	Counts Counts  `json:"counts"`
+	Issues []Issue `json:"issues"`
+}
+
+// summaryIssueLimit bounds the number of issues listed by Summary
+const summaryIssueLimit = 20
+
+// parentFields names the parent ID field of each level below district
+var parentFields = map[Level]string{
+	LevelCounty:    "district_id",
+	LevelSubcounty: "county_id",
+	LevelParish:    "subcounty_id",
+	LevelVillage:   "parish_id",
+}
+
+// Validate loads the whole hierarchy through api and checks its referential
+// integrity
+func Validate(ctx context.Context, api API) (*ValidationReport, error) {
+	ds, err := LoadDataset(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateDataset(ds), nil
+}
+
+// ValidateDataset checks ds for blank required fields, missing and dangling
+// parent references, orphans, duplicate IDs and codes repeated under the same
+// parent. Issues are ordered by level and then by the order of the units in
+// ds.
+func ValidateDataset(ds *Dataset) *ValidationReport {
+	report := &ValidationReport{
+		Counts: Counts{
+			Districts:   len(ds.Districts),
+			Counties:    len(ds.Counties),
+			Subcounties: len(ds.Subcounties),
+			Parishes:    len(ds.Parishes),
+			Villages:    len(ds.Villages),
+		},
+		Issues: []Issue{},
+	}
+
+	levels := ds.units()
+	ids := make(map[Level]map[string]bool)
+	reachable := make(map[Level]map[string]bool)
+
+	for _, level := range Levels {
+		ids[level] = make(map[string]bool)
+		for _, u := range levels[level] {
+			if u.UnitID() != "" {
+				ids[level][u.UnitID()] = true
+			}
+		}
+	}
+
+	for _, level := range Levels {
+		reachable[level] = make(map[string]bool)
+		seen := make(map[string]bool)
+		codes := make(map[string]bool)
+
+		for _, u := range levels[level] {
+			id := u.UnitID()
+
+			if id == "" {
+				report.add(u, IssueEmptyField, "id", "", "id is blank")
+			} else if seen[id] {
+				report.add(u, IssueDuplicateID, "id", id, fmt.Sprintf("id %q is used more than once", id))
+			}
+			seen[id] = true
+
+			if strings.TrimSpace(u.UnitName()) == "" {
+				report.add(u, IssueEmptyField, "name", "", "name is blank")
+			}
+
+			if level == LevelDistrict {
+				if id != "" {
+					reachable[level][id] = true
+				}
+				continue
+			}
+
+			field, parentID := parentFields[level], u.ParentID()
+			switch {
+			case parentID == "":
+				report.add(u, IssueMissingParent, field, "", field+" is blank")
+				continue
+			case !ids[level.Parent()][parentID]:
+				report.add(u, IssueDanglingParent, field, parentID, fmt.Sprintf("%s %q does not exist", level.Parent(), parentID))
+				continue
+			case !reachable[level.Parent()][parentID]:
+				report.add(u, IssueOrphan, field, parentID, fmt.Sprintf("%s %q is not linked to a district", level.Parent(), parentID))
+				continue
+			}
+			if id != "" {
+				reachable[level][id] = true
+			}
+
+			if code := strings.TrimSpace(u.UnitCode()); code != "" {
+				key := parentID + "\x00" + strings.ToLower(code)
+				if codes[key] {
+					report.add(u, IssueDuplicateCode, "code", code, fmt.Sprintf("code %q is used more than once in %s %q", code, level.Parent(), parentID))
+				}
+				codes[key] = true
+			}
+		}
+	}
+
+	return report
+}
+
+func (r *ValidationReport) add(u AdminUnit, kind IssueKind, field, value, message string) {
+	r.Issues = append(r.Issues, Issue{
+		Kind:    kind,
+		Level:   u.Level(),
+		ID:      u.UnitID(),
+		Field:   field,
+		Value:   value,
+		Message: message,
+	})
+}
+
+// Valid reports whether no issues were found
+func (r *ValidationReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// IssueCounts returns the number of issues of each kind
+func (r *ValidationReport) IssueCounts() map[IssueKind]int {
+	counts := make(map[IssueKind]int)
+	for _, issue := range r.Issues {
+		counts[issue.Kind]++
+	}
+	return counts
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *ValidationReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Summary returns a short human-readable description of the report
+func (r *ValidationReport) Summary() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Checked %d districts, %d counties, %d subcounties, %d parishes and %d villages\n",
+		r.Counts.Districts, r.Counts.Counties, r.Counts.Subcounties, r.Counts.Parishes, r.Counts.Villages)
+
+	if r.Valid() {
+		b.WriteString("No issues found\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Found %d issues:\n", len(r.Issues))
+
+	counts := r.IssueCounts()
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(&b, "  %-16s %d\n", kind, counts[IssueKind(kind)])
+	}
+
+	b.WriteString("\n")
+	for i, issue := range r.Issues {
+		if i == summaryIssueLimit {
+			fmt.Fprintf(&b, "... and %d more\n", len(r.Issues)-summaryIssueLimit)
+			break
+		}
+		fmt.Fprintf(&b, "%s %q: %s\n", issue.Level, issue.ID, issue.Message)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package opendataug
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateDataset(t *testing.T) {
+	ds := testDataset()
+	ds.Counties = append(ds.Counties, County{ID: "county-4", Name: "Blank", DistrictID: ""})
+	ds.Subcounties = append(ds.Subcounties, Subcounty{ID: "subcounty-4", Name: "Under Blank", CountyID: "county-4"})
+	ds.Parishes = append(ds.Parishes,
+		Parish{ID: "parish-1", Name: "Kiwatule Again", SubcountyID: "subcounty-1"},
+		Parish{ID: "parish-5", Name: " ", Code: "KWT", SubcountyID: "subcounty-1"},
+	)
+
+	report := ValidateDataset(ds)
+
+	type issue struct {
+		Kind  IssueKind
+		Level Level
+		ID    string
+	}
+	var got []issue
+	for _, i := range report.Issues {
+		got = append(got, issue{Kind: i.Kind, Level: i.Level, ID: i.ID})
+	}
+
+	expected := []issue{
+		{Kind: IssueMissingParent, Level: LevelCounty, ID: "county-4"},
+		{Kind: IssueOrphan, Level: LevelSubcounty, ID: "subcounty-4"},
+		{Kind: IssueDuplicateID, Level: LevelParish, ID: "parish-1"},
+		{Kind: IssueEmptyField, Level: LevelParish, ID: "parish-5"},
+		{Kind: IssueDuplicateCode, Level: LevelParish, ID: "parish-5"},
+		{Kind: IssueDanglingParent, Level: LevelVillage, ID: "village-9"},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	if report.Valid() {
+		t.Error("Expected the report not to be valid")
+	}
+
+	if report.Counts.Parishes != 6 {
+		t.Errorf("Expected 6 parishes to be counted, got %d", report.Counts.Parishes)
+	}
+}
+
+func TestValidateDatasetValid(t *testing.T) {
+	ds := testDataset()
+	ds.Villages = ds.Villages[:5]
+
+	report := ValidateDataset(ds)
+	if !report.Valid() {
+		t.Errorf("Expected no issues, got %+v", report.Issues)
+	}
+
+	if !strings.Contains(report.Summary(), "No issues found") {
+		t.Errorf("Expected the summary to report no issues, got %q", report.Summary())
+	}
+}
+
+func TestValidationReportOutput(t *testing.T) {
+	report := ValidateDataset(testDataset())
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var decoded ValidationReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, report) {
+		t.Errorf("Expected %+v, got %+v", report, decoded)
+	}
+
+	summary := report.Summary()
+	for _, want := range []string{"Found 1 issues", "dangling_parent", `village "village-9"`} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected the summary to contain %q, got %q", want, summary)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	server, client := TestRoutesServer(t, datasetRoutes(t, testDataset()))
+	defer server.Close()
+
+	report, err := Validate(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(report.Issues) != 1 || report.Issues[0].ID != "village-9" {
+		t.Errorf("Expected a single issue for village-9, got %+v", report.Issues)
+	}
+}
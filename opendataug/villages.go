@@ -1,32 +1,34 @@
 package opendataug
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
 
 // GetVillages retrieves all villages
 func (c *Client) GetVillages() ([]Village, error) {
-	var response struct {
-		Data []Village `json:"data"`
-	}
-
-	err := c.doRequest(http.MethodGet, "/villages", &response)
-	if err != nil {
-		return nil, err
-	}
+	return c.GetVillagesContext(context.Background())
+}
 
-	return response.Data, nil
+// GetVillagesContext retrieves all villages using ctx
+func (c *Client) GetVillagesContext(ctx context.Context) ([]Village, error) {
+	return getList[Village](ctx, c, "/villages")
 }
 
 // GetVillage retrieves a specific village by ID
-func (c *Client) GetVillage(id string) (*Village, error) {
+func (c *Client) GetVillage(id VillageID) (*Village, error) {
+	return c.GetVillageContext(context.Background(), id)
+}
+
+// GetVillageContext retrieves a specific village by ID using ctx
+func (c *Client) GetVillageContext(ctx context.Context, id VillageID) (*Village, error) {
 	var response struct {
 		Data Village `json:"data"`
 	}
 
 	path := fmt.Sprintf("/villages/%s", id)
-	err := c.doRequest(http.MethodGet, path, &response)
+	err := c.doRequestContext(ctx, http.MethodGet, path, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -35,16 +37,12 @@ func (c *Client) GetVillage(id string) (*Village, error) {
 }
 
 // GetVillagesByParish retrieves all villages in a specific parish
-func (c *Client) GetVillagesByParish(parishID string) ([]Village, error) {
-	var response struct {
-		Data []Village `json:"data"`
-	}
+func (c *Client) GetVillagesByParish(parishID ParishID) ([]Village, error) {
+	return c.GetVillagesByParishContext(context.Background(), parishID)
+}
 
+// GetVillagesByParishContext retrieves all villages in a specific parish using ctx
+func (c *Client) GetVillagesByParishContext(ctx context.Context, parishID ParishID) ([]Village, error) {
 	path := fmt.Sprintf("/parishes/%s/villages", parishID)
-	err := c.doRequest(http.MethodGet, path, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return response.Data, nil
+	return getList[Village](ctx, c, path)
 }
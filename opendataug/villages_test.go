@@ -55,7 +55,7 @@ func TestGetVillages(t *testing.T) {
 func TestGetVillage(t *testing.T) {
 	tests := []struct {
 		name           string
-		villageID      string
+		villageID      VillageID
 		expectedPath   string
 		response       string
 		expectedResult *Village
@@ -118,7 +118,7 @@ func TestGetVillage(t *testing.T) {
 func TestGetVillagesByParish(t *testing.T) {
 	tests := []struct {
 		name           string
-		parishID       string
+		parishID       ParishID
 		expectedPath   string
 		response       string
 		expectedResult []Village
@@ -0,0 +1,215 @@
+package opendataug
+
+import (
+	"errors"
+	"iter"
+)
+
+// SkipChildren is returned by Visitor.Enter to skip the children of a unit
+var SkipChildren = errors.New("opendataug: skip children")
+
+// SkipAll is returned by a Visitor to stop the walk. Walk then returns nil.
+var SkipAll = errors.New("opendataug: skip all")
+
+// Visitor is called by Walk for every unit. Enter is called before the
+// children of the unit are visited and Leave after them. If Enter returns
+// SkipChildren the children are skipped but Leave is still called.
+type Visitor interface {
+	Enter(unit AdminUnit) error
+	Leave(unit AdminUnit) error
+}
+
+// VisitorFunc adapts a function to a Visitor that only acts on Enter
+type VisitorFunc func(unit AdminUnit) error
+
+// Enter calls f(unit)
+func (f VisitorFunc) Enter(unit AdminUnit) error { return f(unit) }
+
+// Leave does nothing
+func (f VisitorFunc) Leave(unit AdminUnit) error { return nil }
+
+// Unit returns the unit of the given level and ID
+func (t *AdminTree) Unit(level Level, id string) (AdminUnit, bool) {
+	var (
+		unit AdminUnit
+		ok   bool
+	)
+	switch level {
+	case LevelDistrict:
+		unit, ok = t.District(DistrictID(id))
+	case LevelCounty:
+		unit, ok = t.County(CountyID(id))
+	case LevelSubcounty:
+		unit, ok = t.Subcounty(SubcountyID(id))
+	case LevelParish:
+		unit, ok = t.Parish(ParishID(id))
+	case LevelVillage:
+		unit, ok = t.Village(VillageID(id))
+	}
+	if !ok {
+		return nil, false
+	}
+	return unit, true
+}
+
+// Roots returns the districts, which are the roots of the tree
+func (t *AdminTree) Roots() []AdminUnit {
+	return units(t.districts)
+}
+
+// Parent returns the unit one level above u
+func (t *AdminTree) Parent(u AdminUnit) (AdminUnit, bool) {
+	if u.Level() == LevelDistrict {
+		return nil, false
+	}
+	return t.Unit(u.Level().Parent(), u.ParentID())
+}
+
+// Children returns the units one level below u
+func (t *AdminTree) Children(u AdminUnit) []AdminUnit {
+	switch u.Level() {
+	case LevelDistrict:
+		return units(t.CountiesOf(DistrictID(u.UnitID())))
+	case LevelCounty:
+		return units(t.SubcountiesOf(CountyID(u.UnitID())))
+	case LevelSubcounty:
+		return units(t.ParishesOf(SubcountyID(u.UnitID())))
+	case LevelParish:
+		return units(t.VillagesOf(ParishID(u.UnitID())))
+	}
+	return nil
+}
+
+// Walk visits every unit linked into the tree depth-first, starting with
+// each district in turn
+func (t *AdminTree) Walk(v Visitor) error {
+	for _, root := range t.Roots() {
+		if err := t.walk(root, v); err != nil {
+			if err == SkipAll {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkFrom visits root and every unit below it depth-first
+func (t *AdminTree) WalkFrom(root AdminUnit, v Visitor) error {
+	if err := t.walk(root, v); err != nil && err != SkipAll {
+		return err
+	}
+	return nil
+}
+
+func (t *AdminTree) walk(u AdminUnit, v Visitor) error {
+	err := v.Enter(u)
+	if err != nil && err != SkipChildren {
+		return err
+	}
+
+	if err == nil {
+		for _, child := range t.Children(u) {
+			if err := t.walk(child, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return v.Leave(u)
+}
+
+// DepthFirst returns an iterator over every unit linked into the tree in
+// depth-first order. If prune is not nil, units for which it returns true are
+// skipped together with everything below them.
+func (t *AdminTree) DepthFirst(prune func(AdminUnit) bool) iter.Seq[AdminUnit] {
+	return func(yield func(AdminUnit) bool) {
+		for _, root := range t.Roots() {
+			if !t.depthFirst(root, prune, yield) {
+				return
+			}
+		}
+	}
+}
+
+// Descendants returns an iterator over every unit below root in depth-first
+// order
+func (t *AdminTree) Descendants(root AdminUnit) iter.Seq[AdminUnit] {
+	return func(yield func(AdminUnit) bool) {
+		for _, child := range t.Children(root) {
+			if !t.depthFirst(child, nil, yield) {
+				return
+			}
+		}
+	}
+}
+
+func (t *AdminTree) depthFirst(u AdminUnit, prune func(AdminUnit) bool, yield func(AdminUnit) bool) bool {
+	if prune != nil && prune(u) {
+		return true
+	}
+	if !yield(u) {
+		return false
+	}
+	for _, child := range t.Children(u) {
+		if !t.depthFirst(child, prune, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// BreadthFirst returns an iterator over every unit linked into the tree level
+// by level. If prune is not nil, units for which it returns true are skipped
+// together with everything below them.
+func (t *AdminTree) BreadthFirst(prune func(AdminUnit) bool) iter.Seq[AdminUnit] {
+	return func(yield func(AdminUnit) bool) {
+		queue := t.Roots()
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+
+			if prune != nil && prune(u) {
+				continue
+			}
+			if !yield(u) {
+				return
+			}
+			queue = append(queue, t.Children(u)...)
+		}
+	}
+}
+
+// Subtree returns root followed by every unit below it in depth-first order
+func (t *AdminTree) Subtree(root AdminUnit) []AdminUnit {
+	subtree := []AdminUnit{root}
+	for u := range t.Descendants(root) {
+		subtree = append(subtree, u)
+	}
+	return subtree
+}
+
+// Aggregate computes a value for every unit linked into the tree from the
+// unit itself and the values of its children, visiting children before their
+// parents. The result is keyed by unit.
+func Aggregate[T any](t *AdminTree, fn func(unit AdminUnit, children []T) T) map[UnitKey]T {
+	values := make(map[UnitKey]T)
+
+	var aggregate func(u AdminUnit) T
+	aggregate = func(u AdminUnit) T {
+		children := t.Children(u)
+		childValues := make([]T, len(children))
+		for i, child := range children {
+			childValues[i] = aggregate(child)
+		}
+		value := fn(u, childValues)
+		values[KeyOf(u)] = value
+		return value
+	}
+
+	for _, root := range t.Roots() {
+		aggregate(root)
+	}
+
+	return values
+}
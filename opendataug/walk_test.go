@@ -0,0 +1,201 @@
+package opendataug
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func unitIDs(units []AdminUnit) []string {
+	ids := make([]string, len(units))
+	for i, u := range units {
+		ids[i] = u.UnitID()
+	}
+	return ids
+}
+
+type recordingVisitor struct {
+	events []string
+	skip   string
+	stop   string
+}
+
+func (v *recordingVisitor) Enter(u AdminUnit) error {
+	v.events = append(v.events, "enter "+u.UnitID())
+	switch u.UnitID() {
+	case v.skip:
+		return SkipChildren
+	case v.stop:
+		return SkipAll
+	}
+	return nil
+}
+
+func (v *recordingVisitor) Leave(u AdminUnit) error {
+	v.events = append(v.events, "leave "+u.UnitID())
+	return nil
+}
+
+func TestAdminTreeWalk(t *testing.T) {
+	tree := NewAdminTree(testDataset())
+
+	v := &recordingVisitor{skip: "subcounty-1", stop: "county-3"}
+	if err := tree.Walk(v); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"enter district-1",
+		"enter county-1",
+		"enter subcounty-1",
+		"leave subcounty-1",
+		"leave county-1",
+		"enter county-2",
+		"enter subcounty-2",
+		"enter parish-3",
+		"enter village-4",
+		"leave village-4",
+		"leave parish-3",
+		"leave subcounty-2",
+		"leave county-2",
+		"leave district-1",
+		"enter district-2",
+		"enter county-3",
+	}
+	if !reflect.DeepEqual(v.events, expected) {
+		t.Errorf("Expected %v, got %v", expected, v.events)
+	}
+}
+
+func TestAdminTreeWalkError(t *testing.T) {
+	tree := NewAdminTree(testDataset())
+	errStop := errors.New("stop")
+
+	var visited []string
+	err := tree.Walk(VisitorFunc(func(u AdminUnit) error {
+		visited = append(visited, u.UnitID())
+		if u.Level() == LevelParish {
+			return errStop
+		}
+		return nil
+	}))
+
+	if !errors.Is(err, errStop) {
+		t.Errorf("Expected %v, got %v", errStop, err)
+	}
+
+	expected := []string{"district-1", "county-1", "subcounty-1", "parish-1"}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("Expected %v, got %v", expected, visited)
+	}
+}
+
+func TestAdminTreeDepthFirst(t *testing.T) {
+	tree := NewAdminTree(testDataset())
+
+	// Only urban districts
+	prune := func(u AdminUnit) bool {
+		d, ok := u.(District)
+		return ok && !d.TownStatus
+	}
+
+	var visited []AdminUnit
+	for u := range tree.DepthFirst(prune) {
+		visited = append(visited, u)
+	}
+
+	expected := []string{
+		"district-1", "county-1", "subcounty-1", "parish-1", "village-1", "village-2",
+		"parish-2", "village-3", "county-2", "subcounty-2", "parish-3", "village-4",
+	}
+	if !reflect.DeepEqual(unitIDs(visited), expected) {
+		t.Errorf("Expected %v, got %v", expected, unitIDs(visited))
+	}
+
+	var first []AdminUnit
+	for u := range tree.DepthFirst(nil) {
+		first = append(first, u)
+		if len(first) == 3 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(unitIDs(first), []string{"district-1", "county-1", "subcounty-1"}) {
+		t.Errorf("Expected iteration to stop after 3 units, got %v", unitIDs(first))
+	}
+}
+
+func TestAdminTreeBreadthFirst(t *testing.T) {
+	tree := NewAdminTree(testDataset())
+
+	var visited []AdminUnit
+	for u := range tree.BreadthFirst(func(u AdminUnit) bool { return u.Level() == LevelParish }) {
+		visited = append(visited, u)
+	}
+
+	expected := []string{
+		"district-1", "district-2", "county-1", "county-2", "county-3",
+		"subcounty-1", "subcounty-2", "subcounty-3",
+	}
+	if !reflect.DeepEqual(unitIDs(visited), expected) {
+		t.Errorf("Expected %v, got %v", expected, unitIDs(visited))
+	}
+}
+
+func TestAdminTreeSubtree(t *testing.T) {
+	tree := NewAdminTree(testDataset())
+
+	subcounty, _ := tree.Subcounty("subcounty-1")
+	subtree := tree.Subtree(subcounty)
+
+	expected := []string{"subcounty-1", "parish-1", "village-1", "village-2", "parish-2", "village-3"}
+	if !reflect.DeepEqual(unitIDs(subtree), expected) {
+		t.Errorf("Expected %v, got %v", expected, unitIDs(subtree))
+	}
+
+	parent, ok := tree.Parent(subcounty)
+	if !ok || parent.UnitID() != "county-1" {
+		t.Errorf("Expected county-1, got %v", parent)
+	}
+
+	district, _ := tree.District("district-1")
+	if _, ok := tree.Parent(district); ok {
+		t.Error("Expected districts to have no parent")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	tree := NewAdminTree(testDataset())
+
+	villages := Aggregate(tree, func(u AdminUnit, children []int) int {
+		if u.Level() == LevelVillage {
+			return 1
+		}
+		total := 0
+		for _, n := range children {
+			total += n
+		}
+		return total
+	})
+
+	tests := []struct {
+		key      UnitKey
+		expected int
+	}{
+		{key: UnitKey{Level: LevelDistrict, ID: "district-1"}, expected: 4},
+		{key: UnitKey{Level: LevelDistrict, ID: "district-2"}, expected: 1},
+		{key: UnitKey{Level: LevelSubcounty, ID: "subcounty-1"}, expected: 3},
+		{key: UnitKey{Level: LevelVillage, ID: "village-3"}, expected: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.key.String(), func(t *testing.T) {
+			if villages[tc.key] != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, villages[tc.key])
+			}
+		})
+	}
+
+	if _, ok := villages[UnitKey{Level: LevelVillage, ID: "village-9"}]; ok {
+		t.Error("Expected orphans not to be aggregated")
+	}
+}